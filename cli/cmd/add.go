@@ -2,9 +2,9 @@ package cmd
 
 import (
 	"fmt"
-	"time"
 
 	"github.com/fatih/color"
+	"github.com/groo-dev/cl-wrangler/cli/internal/audit"
 	"github.com/groo-dev/cl-wrangler/cli/internal/store"
 	"github.com/groo-dev/cl-wrangler/cli/internal/wrangler"
 	"github.com/spf13/cobra"
@@ -17,7 +17,10 @@ var addCmd = &cobra.Command{
 	RunE:  runAdd,
 }
 
+var addConfirm string
+
 func init() {
+	addCmd.Flags().StringVar(&addConfirm, "confirm", "", "Name of the protected account being saved, instead of typing it")
 	rootCmd.AddCommand(addCmd)
 }
 
@@ -27,15 +30,16 @@ func runAdd(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to load database: %w", err)
 	}
 
-	// Ensure we have a working wrangler command
-	wranglerCmd, err := wrangler.EnsureWranglerCmd(db)
+	// Ensure we have a working wrangler command; the new login isn't
+	// necessarily the current profile, so its pin doesn't apply
+	wranglerCmd, err := wrangler.EnsureProfileCmd(db, nil)
 	if err != nil {
 		return fmt.Errorf("failed to find wrangler: %w", err)
 	}
 
 	// Get current account info from wrangler
 	fmt.Println("Getting account info from wrangler...")
-	info, err := wrangler.Whoami(wranglerCmd)
+	info, err := wrangler.Whoami(cmd.Context(), wranglerCmd)
 	if err != nil {
 		return err
 	}
@@ -46,6 +50,21 @@ func runAdd(cmd *cobra.Command, args []string) error {
 		fmt.Printf("Account '%s' already saved. Updating...\n", existing.Name)
 	}
 
+	account := db.AccountForLogin(info.AccountID, info.AccountName, info.Email)
+
+	// Saving makes the account current, which a protected one must confirm
+	switching := account.ID != db.Current
+	if switching {
+		if err := confirmProtected(&account, addConfirm); err != nil {
+			return err
+		}
+	}
+
+	oldAcc := db.GetAccount(db.Current)
+	if err := runPreSwitchHooks(db, "add", &account); err != nil {
+		return err
+	}
+
 	// Save the config file
 	configHash, err := store.SaveAccountConfig(info.AccountID)
 	if err != nil {
@@ -53,22 +72,21 @@ func runAdd(cmd *cobra.Command, args []string) error {
 	}
 
 	// Add to database
-	account := store.Account{
-		ID:         info.AccountID,
-		Name:       info.AccountName,
-		Email:      info.Email,
-		AddedAt:    time.Now(),
-		ConfigHash: configHash,
-	}
+	account.ConfigHash = configHash
 	db.AddAccount(account)
-	db.Current = info.AccountID
+	db.SetCurrent(info.AccountID)
 
 	if err := store.SaveDB(db); err != nil {
 		return fmt.Errorf("failed to save database: %w", err)
 	}
 
+	recordEvent(audit.ActionAdd, &account, oldAcc, &account)
 	color.Green("✓ Account saved: %s (%s)", info.AccountName, info.Email)
 	color.Cyan("  Account ID: %s", info.AccountID)
+	runPostSwitchHooks(db, "add", oldAcc)
 
-	return nil
+	if !switching {
+		return nil
+	}
+	return armRevert(db, &account, account.RevertAfter)
 }
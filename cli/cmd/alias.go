@@ -0,0 +1,126 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/groo-dev/cl-wrangler/cli/internal/store"
+	"github.com/spf13/cobra"
+)
+
+var aliasCmd = &cobra.Command{
+	Use:   "alias",
+	Short: "Manage account aliases",
+	Long: `Aliases are short names for a saved account.
+They can be used anywhere an account name or ID is accepted, e.g. 'cl switch prod'.`,
+}
+
+var aliasAddCmd = &cobra.Command{
+	Use:               "add <account-name-or-id> <alias>...",
+	Short:             "Add aliases to an account",
+	Args:              cobra.MinimumNArgs(2),
+	RunE:              runAliasAdd,
+	ValidArgsFunction: completeAccountNames,
+}
+
+var aliasRemoveCmd = &cobra.Command{
+	Use:               "remove <account-name-or-id> <alias>...",
+	Aliases:           []string{"rm"},
+	Short:             "Remove aliases from an account",
+	Args:              cobra.MinimumNArgs(2),
+	RunE:              runAliasRemove,
+	ValidArgsFunction: completeAccountNames,
+}
+
+func init() {
+	aliasCmd.AddCommand(aliasAddCmd)
+	aliasCmd.AddCommand(aliasRemoveCmd)
+	rootCmd.AddCommand(aliasCmd)
+}
+
+func runAliasAdd(cmd *cobra.Command, args []string) error {
+	db, err := store.LoadDB()
+	if err != nil {
+		return fmt.Errorf("failed to load database: %w", err)
+	}
+
+	targetID, err := findAccount(db, args[0])
+	if err != nil {
+		return err
+	}
+	acc := db.GetAccount(targetID)
+
+	for _, alias := range args[1:] {
+		if err := validateAlias(db, alias, targetID); err != nil {
+			return err
+		}
+		if !acc.HasAlias(alias) {
+			acc.Aliases = append(acc.Aliases, alias)
+		}
+	}
+
+	db.AddAccount(*acc)
+	if err := store.SaveDB(db); err != nil {
+		return fmt.Errorf("failed to save database: %w", err)
+	}
+
+	color.Green("✓ Aliases for %s: %s", acc.Name, strings.Join(acc.Aliases, ", "))
+
+	return nil
+}
+
+func runAliasRemove(cmd *cobra.Command, args []string) error {
+	db, err := store.LoadDB()
+	if err != nil {
+		return fmt.Errorf("failed to load database: %w", err)
+	}
+
+	targetID, err := findAccount(db, args[0])
+	if err != nil {
+		return err
+	}
+	acc := db.GetAccount(targetID)
+
+	for _, alias := range args[1:] {
+		if !acc.HasAlias(alias) {
+			return fmt.Errorf("%s has no alias '%s'", acc.Name, alias)
+		}
+		var kept []string
+		for _, existing := range acc.Aliases {
+			if !strings.EqualFold(existing, alias) {
+				kept = append(kept, existing)
+			}
+		}
+		acc.Aliases = kept
+	}
+
+	db.AddAccount(*acc)
+	if err := store.SaveDB(db); err != nil {
+		return fmt.Errorf("failed to save database: %w", err)
+	}
+
+	color.Green("✓ Removed alias(es) from %s", acc.Name)
+
+	return nil
+}
+
+// validateAlias makes sure an alias is usable and doesn't belong to another account
+func validateAlias(db *store.AccountsDB, alias, accountID string) error {
+	if alias == "" || alias == "-" || strings.ContainsAny(alias, " \t\n") {
+		return fmt.Errorf("invalid alias '%s'", alias)
+	}
+	if other := db.GetAccount(alias); other != nil {
+		return fmt.Errorf("alias '%s' is the ID of %s", alias, other.Name)
+	}
+	if other := db.FindByAlias(alias); other != nil && other.ID != accountID {
+		return fmt.Errorf("alias '%s' is already used by %s", alias, other.Name)
+	}
+	// Names and aliases rank the same, so this would make the name ambiguous
+	for _, other := range db.Accounts {
+		if other.ID != accountID && strings.EqualFold(other.Name, alias) {
+			return fmt.Errorf("alias '%s' is the name of %s", alias, other.Name)
+		}
+	}
+	return nil
+}
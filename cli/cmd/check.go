@@ -0,0 +1,249 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/groo-dev/cl-wrangler/cli/internal/check"
+	"github.com/groo-dev/cl-wrangler/cli/internal/store"
+	"github.com/groo-dev/cl-wrangler/cli/internal/wrangler"
+	"github.com/spf13/cobra"
+)
+
+// Exit codes of 'cl check', from best to worst
+const (
+	checkExitExpired = 2 // some tokens expired, nothing worse
+	checkExitFailed  = 3 // some tokens revoked, mismatched or not checkable
+)
+
+var checkCmd = &cobra.Command{
+	Use:   "check [account-name-or-id...]",
+	Short: "Verify that saved accounts still work",
+	Long: `Verifies the token of the current account, the given accounts, every account (--all)
+or the accounts with a tag (--tag), several at a time. Each token is checked against the
+Cloudflare API, or with 'wrangler whoami' using only that token (--wrangler), and the
+account ID must still be accessible. Expired OAuth tokens are refreshed first and
+only reported as expired if that fails.
+
+Exit codes: 0 all valid, 2 some expired, 3 some revoked, mismatched or failed.`,
+	Example: `  cl check --all
+  cl check --tag prod --output json`,
+	RunE:              runCheck,
+	ValidArgsFunction: completeAccountNames,
+}
+
+var (
+	checkAll      bool
+	checkTags     []string
+	checkWorkers  int
+	checkTimeout  time.Duration
+	checkWrangler bool
+	checkOutput   *outputOptions
+)
+
+func init() {
+	checkCmd.Flags().BoolVar(&checkAll, "all", false, "Check every saved account")
+	checkCmd.Flags().StringSliceVar(&checkTags, "tag", nil, "Check accounts with this tag (repeatable)")
+	checkCmd.Flags().IntVar(&checkWorkers, "workers", 4, "Accounts checked at the same time")
+	checkCmd.Flags().DurationVar(&checkTimeout, "timeout", 15*time.Second, "Time limit per account")
+	checkCmd.Flags().BoolVar(&checkWrangler, "wrangler", false, "Check with 'wrangler whoami' instead of the API")
+	checkCmd.RegisterFlagCompletionFunc("tag", completeTags)
+	checkOutput = addOutputFlags(checkCmd)
+	rootCmd.AddCommand(checkCmd)
+}
+
+// checkResultView is the machine-readable schema for a check result
+type checkResultView struct {
+	ID         string `json:"id" yaml:"id"`
+	Name       string `json:"name" yaml:"name"`
+	Email      string `json:"email" yaml:"email"`
+	Status     string `json:"status" yaml:"status"`
+	Detail     string `json:"detail" yaml:"detail"`
+	DurationMS int64  `json:"duration_ms" yaml:"duration_ms"`
+}
+
+type checkResultListView []checkResultView
+
+func (l checkResultListView) Header() []string {
+	return []string{"id", "name", "email", "status", "detail", "duration_ms"}
+}
+
+func (l checkResultListView) Rows() [][]string {
+	rows := make([][]string, 0, len(l))
+	for _, v := range l {
+		rows = append(rows, []string{v.ID, v.Name, v.Email, v.Status, v.Detail, strconv.FormatInt(v.DurationMS, 10)})
+	}
+	return rows
+}
+
+func runCheck(cmd *cobra.Command, args []string) error {
+	structured, err := checkOutput.structured()
+	if err != nil {
+		return err
+	}
+
+	db, err := store.LoadDB()
+	if err != nil {
+		return fmt.Errorf("failed to load database: %w", err)
+	}
+
+	accounts, err := checkTargets(db, args)
+	if err != nil {
+		return err
+	}
+
+	var base wrangler.Cmd
+	probe := check.APIProbe
+	if checkWrangler {
+		base, err = wrangler.EnsureWranglerCmd(db)
+		if err != nil {
+			return fmt.Errorf("failed to find wrangler: %w", err)
+		}
+		probe = wranglerProbe
+	}
+
+	// Refreshing saves the token and the database, one account at a time
+	var refreshMu sync.Mutex
+	targets := make([]check.Target, 0, len(accounts))
+	for _, acc := range accounts {
+		target := check.Target{Account: acc}
+		if info, err := db.GetAccountTokenInfo(acc.ID); err == nil {
+			target.Token = info
+		}
+		target.Refresh = func() (*store.TokenInfo, error) {
+			refreshMu.Lock()
+			defer refreshMu.Unlock()
+			return freshTokenInfo(db, &acc)
+		}
+		if checkWrangler {
+			// Resolve pinned commands here; EnsureProfileCmd may save the database
+			target.Cmd = base
+			if cmd, err := wrangler.EnsureProfileCmd(db, &acc); err == nil {
+				target.Cmd = cmd
+			}
+		}
+		targets = append(targets, target)
+	}
+
+	results := check.Run(cmd.Context(), targets, checkWorkers, checkTimeout, probe)
+
+	if structured {
+		views := checkResultListView{}
+		for _, r := range results {
+			views = append(views, checkResultView{
+				ID:         r.Account.ID,
+				Name:       r.Account.Name,
+				Email:      r.Account.Email,
+				Status:     string(r.Status),
+				Detail:     r.Detail,
+				DurationMS: r.Duration.Milliseconds(),
+			})
+		}
+		if err := checkOutput.print(views); err != nil {
+			return err
+		}
+	} else {
+		printCheckResults(results)
+	}
+
+	if code := checkExitCode(results); code != 0 {
+		os.Exit(code)
+	}
+	return nil
+}
+
+// checkTargets picks the accounts to check from the arguments and flags
+func checkTargets(db *store.AccountsDB, args []string) ([]store.Account, error) {
+	var accounts []store.Account
+	switch {
+	case len(args) > 0:
+		if checkAll || len(checkTags) > 0 {
+			return nil, fmt.Errorf("accounts cannot be combined with --all or --tag")
+		}
+		for _, query := range args {
+			targetID, err := findAccount(db, query)
+			if err != nil {
+				return nil, err
+			}
+			accounts = append(accounts, *db.GetAccount(targetID))
+		}
+	case checkAll || len(checkTags) > 0:
+		for _, acc := range db.Accounts {
+			if acc.HasAnyTag(checkTags) {
+				accounts = append(accounts, acc)
+			}
+		}
+	default:
+		acc := db.GetAccount(db.Current)
+		if acc == nil {
+			return nil, fmt.Errorf("no current account set; pass an account, --all or --tag")
+		}
+		accounts = append(accounts, *acc)
+	}
+
+	if len(accounts) == 0 {
+		return nil, fmt.Errorf("no accounts to check")
+	}
+	return accounts, nil
+}
+
+// wranglerProbe runs 'wrangler whoami' with only the profile's token in the
+// environment, so the live wrangler config is neither used nor touched
+func wranglerProbe(ctx context.Context, t check.Target) (check.Status, string) {
+	acc := t.Account
+	cmd := t.Cmd
+	cmd.Env = append(append([]string{}, cmd.Env...), credentialEnv(&acc, t.Token)...)
+
+	info, err := wrangler.Whoami(ctx, cmd)
+	if err != nil {
+		return check.StatusError, err.Error()
+	}
+	if !slices.Contains(info.AccountIDs, acc.ID) {
+		return check.StatusMismatched, fmt.Sprintf("token works but can't access account %s", acc.ID)
+	}
+	return check.StatusValid, ""
+}
+
+func printCheckResults(results []check.Result) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ACCOUNT\tID\tSTATUS\tDETAIL")
+	for _, r := range results {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", r.Account.Name, r.Account.ID, colorStatus(r.Status), r.Detail)
+	}
+	w.Flush()
+}
+
+// colorStatus pads before coloring so tabwriter sees the same width for every row
+func colorStatus(status check.Status) string {
+	text := fmt.Sprintf("%-10s", status)
+	switch status {
+	case check.StatusValid:
+		return color.GreenString(text)
+	case check.StatusExpired, check.StatusLoggedOut:
+		return color.YellowString(text)
+	default:
+		return color.RedString(text)
+	}
+}
+
+// checkExitCode returns the exit code for the worst result
+func checkExitCode(results []check.Result) int {
+	code := 0
+	for _, r := range results {
+		switch r.Status {
+		case check.StatusValid, check.StatusLoggedOut:
+		case check.StatusExpired:
+			code = max(code, checkExitExpired)
+		default:
+			code = max(code, checkExitFailed)
+		}
+	}
+	return code
+}
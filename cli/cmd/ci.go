@@ -0,0 +1,216 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/fatih/color"
+	"github.com/groo-dev/cl-wrangler/cli/internal/bundle"
+	"github.com/groo-dev/cl-wrangler/cli/internal/config"
+	"github.com/groo-dev/cl-wrangler/cli/internal/store"
+	"github.com/spf13/cobra"
+)
+
+var ciRestoreCmd = &cobra.Command{
+	Use:   "ci-restore --from-env <VAR>",
+	Short: "Install a profile exported with 'cl export --for-ci'",
+	Long: `Reads a profile bundle from an environment variable and installs it as the active
+wrangler config and the current cl account. Encrypted bundles are decrypted with the
+passphrase in $CL_BUNDLE_PASSPHRASE, or the variable named by --passphrase-env.
+Every file written is recorded so 'cl ci-cleanup' can remove it or put the original back.`,
+	Example: `  cl ci-restore --from-env CL_PROFILE_BUNDLE
+  npx wrangler deploy
+  cl ci-cleanup`,
+	Args: cobra.NoArgs,
+	RunE: runCIRestore,
+}
+
+var ciCleanupCmd = &cobra.Command{
+	Use:   "ci-cleanup",
+	Short: "Remove the files written by 'cl ci-restore'",
+	Long: `Removes the files written by 'cl ci-restore' and puts back any files they replaced,
+including the wrangler config and accounts.json.`,
+	Args: cobra.NoArgs,
+	RunE: runCICleanup,
+}
+
+var (
+	ciFromEnv       string
+	ciPassphraseEnv string
+)
+
+func init() {
+	ciRestoreCmd.Flags().StringVar(&ciFromEnv, "from-env", "", "Environment variable holding the bundle")
+	ciRestoreCmd.Flags().StringVar(&ciPassphraseEnv, "passphrase-env", defaultPassphraseEnv, "Environment variable holding the passphrase")
+	ciRestoreCmd.MarkFlagRequired("from-env")
+	rootCmd.AddCommand(ciRestoreCmd)
+	rootCmd.AddCommand(ciCleanupCmd)
+}
+
+// ciState records the files 'cl ci-restore' wrote
+type ciState struct {
+	AccountID string   `json:"account_id"`
+	Files     []ciFile `json:"files"`
+}
+
+// ciFile is a written file and where its original was moved, if it existed
+type ciFile struct {
+	Path   string `json:"path"`
+	Backup string `json:"backup,omitempty"`
+}
+
+const ciBackupSuffix = ".cl-ci-backup"
+
+func runCIRestore(cmd *cobra.Command, args []string) error {
+	statePath, err := config.GetCIStatePath()
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(statePath); err == nil {
+		return fmt.Errorf("a profile is already restored; run 'cl ci-cleanup' first")
+	}
+
+	value := os.Getenv(ciFromEnv)
+	if value == "" {
+		return fmt.Errorf("$%s is empty or not set", ciFromEnv)
+	}
+
+	b, err := bundle.Decode(value, os.Getenv(ciPassphraseEnv))
+	if errors.Is(err, bundle.ErrPassphraseRequired) {
+		return fmt.Errorf("%w: set $%s", err, ciPassphraseEnv)
+	}
+	if err != nil {
+		return err
+	}
+
+	livePath, err := config.GetWranglerConfigPath()
+	if err != nil {
+		return err
+	}
+	savedPath, err := config.GetAccountConfigPath(b.Account.ID)
+	if err != nil {
+		return err
+	}
+	dbPath, err := config.GetAccountsDBPath()
+	if err != nil {
+		return err
+	}
+
+	db, err := store.LoadDB()
+	if err != nil {
+		return fmt.Errorf("failed to load database: %w", err)
+	}
+
+	// Record the state first so a failure part way can still be cleaned up
+	state := &ciState{AccountID: b.Account.ID}
+	for _, path := range []string{livePath, savedPath, dbPath} {
+		file, err := backupForCI(path)
+		if err != nil {
+			return err
+		}
+		state.Files = append(state.Files, file)
+		if err := saveCIState(statePath, state); err != nil {
+			return err
+		}
+	}
+
+	for _, path := range []string{livePath, savedPath} {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, []byte(b.Config), 0600); err != nil {
+			return fmt.Errorf("failed to write %s: %w", path, err)
+		}
+	}
+
+	acc := b.Account
+	acc.ConfigHash, err = store.HashFile(livePath)
+	if err != nil {
+		return err
+	}
+	db.AddAccount(acc)
+	db.Current = acc.ID
+	if err := store.SaveDB(db); err != nil {
+		return fmt.Errorf("failed to save database: %w", err)
+	}
+
+	color.Green("✓ Restored %s (%s) as the active account", acc.Name, acc.ID)
+	return nil
+}
+
+// backupForCI moves an existing file aside so ci-cleanup can put it back
+func backupForCI(path string) (ciFile, error) {
+	file := ciFile{Path: path}
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return file, nil
+	}
+
+	file.Backup = path + ciBackupSuffix
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return file, err
+	}
+	if err := os.WriteFile(file.Backup, data, 0600); err != nil {
+		return file, fmt.Errorf("failed to back up %s: %w", path, err)
+	}
+	return file, nil
+}
+
+func saveCIState(path string, state *ciState) error {
+	if err := config.EnsureConfigDirs(); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
+
+func runCICleanup(cmd *cobra.Command, args []string) error {
+	statePath, err := config.GetCIStatePath()
+	if err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(statePath)
+	if os.IsNotExist(err) {
+		fmt.Println("Nothing to clean up.")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var state ciState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return fmt.Errorf("invalid %s: %w", statePath, err)
+	}
+
+	failed := 0
+	for _, file := range state.Files {
+		var err error
+		if file.Backup != "" {
+			err = os.Rename(file.Backup, file.Path)
+		} else {
+			err = os.Remove(file.Path)
+		}
+		if err != nil && !os.IsNotExist(err) {
+			color.Red("✗ %s: %v", file.Path, err)
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("failed to clean up %d file(s); run 'cl ci-cleanup' again", failed)
+	}
+
+	if err := os.Remove(statePath); err != nil {
+		return err
+	}
+
+	color.Green("✓ Removed CI profile %s", state.AccountID)
+	return nil
+}
@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/groo-dev/cl-wrangler/cli/internal/store"
+	"github.com/spf13/cobra"
+)
+
+var colorCmd = &cobra.Command{
+	Use:   "color <account-name-or-id> [color]",
+	Short: "Show or set an account's display color",
+	Long: fmt.Sprintf(`Shows the display color of a saved account, or sets it.
+The account name is shown in this color by 'cl list' and the switch menu.
+Colors: %s. Use --clear to remove it.`, strings.Join(store.Colors, ", ")),
+	Args:              cobra.RangeArgs(1, 2),
+	RunE:              runColor,
+	ValidArgsFunction: completeColorArgs,
+}
+
+var colorClear bool
+
+func init() {
+	colorCmd.Flags().BoolVar(&colorClear, "clear", false, "Remove the display color")
+	rootCmd.AddCommand(colorCmd)
+}
+
+// accountColors maps display colors to terminal colors
+var accountColors = map[string]color.Attribute{
+	"red":     color.FgRed,
+	"green":   color.FgGreen,
+	"yellow":  color.FgYellow,
+	"blue":    color.FgBlue,
+	"magenta": color.FgMagenta,
+	"cyan":    color.FgCyan,
+	"white":   color.FgWhite,
+}
+
+func runColor(cmd *cobra.Command, args []string) error {
+	db, err := store.LoadDB()
+	if err != nil {
+		return fmt.Errorf("failed to load database: %w", err)
+	}
+
+	targetID, err := findAccount(db, args[0])
+	if err != nil {
+		return err
+	}
+	acc := db.GetAccount(targetID)
+
+	if len(args) == 1 && !colorClear {
+		if acc.Color == "" {
+			fmt.Printf("No color for %s.\n", acc.Name)
+		} else {
+			fmt.Println(acc.Color)
+		}
+		return nil
+	}
+	if len(args) == 2 && colorClear {
+		return fmt.Errorf("--clear cannot be combined with a color")
+	}
+
+	if colorClear {
+		acc.Color = ""
+	} else {
+		name := strings.ToLower(args[1])
+		if !slices.Contains(store.Colors, name) {
+			return fmt.Errorf("unknown color '%s' (expected one of: %s)", args[1], strings.Join(store.Colors, ", "))
+		}
+		acc.Color = name
+	}
+
+	db.AddAccount(*acc)
+	if err := store.SaveDB(db); err != nil {
+		return fmt.Errorf("failed to save database: %w", err)
+	}
+
+	if colorClear {
+		color.Green("✓ Cleared color for %s", acc.Name)
+	} else {
+		color.Green("✓ Color for %s: %s", accountName(*acc), acc.Color)
+	}
+
+	return nil
+}
+
+// accountName returns the account's name in its display color
+func accountName(acc store.Account) string {
+	attr, ok := accountColors[acc.Color]
+	if !ok {
+		return acc.Name
+	}
+	return color.New(attr).Sprint(acc.Name)
+}
+
+func completeColorArgs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) == 1 {
+		return store.Colors, cobra.ShellCompDirectiveNoFileComp
+	}
+	return completeAccountNames(cmd, args, toComplete)
+}
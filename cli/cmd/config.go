@@ -2,24 +2,76 @@ package cmd
 
 import (
 	"fmt"
+	"os"
+	"strconv"
+	"strings"
 
 	"github.com/charmbracelet/huh"
 	"github.com/groo-dev/cl-wrangler/cli/internal/config"
+	"github.com/groo-dev/cl-wrangler/cli/internal/prompt"
 	"github.com/groo-dev/cl-wrangler/cli/internal/store"
+	"github.com/groo-dev/cl-wrangler/cli/internal/update"
+	"github.com/groo-dev/cl-wrangler/cli/internal/wrangler"
 	"github.com/spf13/cobra"
 )
 
 var configCmd = &cobra.Command{
 	Use:   "config",
 	Short: "View or edit configuration",
-	Long:  `View current configuration or edit settings like wrangler command path.`,
-	RunE:  runConfig,
+	Long: `View current configuration or edit settings like wrangler command path.
+Use --wrangler-cmd, --update-channel or --update-check to change settings without prompting.`,
+	RunE: runConfig,
 }
 
+var (
+	configWranglerCmd   string
+	configUpdateChannel string
+	configUpdateCheck   bool
+	configOutput        *outputOptions
+)
+
+// configSettingFlags are the flags that change settings
+var configSettingFlags = []string{"wrangler-cmd", "update-channel", "update-check"}
+
 func init() {
+	configCmd.Flags().StringVar(&configWranglerCmd, "wrangler-cmd", "", "Set the wrangler command, shell-quoted")
+	configCmd.Flags().StringVar(&configUpdateChannel, "update-channel", "", "Set the update channel: stable, beta")
+	configCmd.Flags().BoolVar(&configUpdateCheck, "update-check", true, "Enable or disable update checks")
+	configOutput = addOutputFlags(configCmd)
 	rootCmd.AddCommand(configCmd)
 }
 
+// configView is the machine-readable schema for cl configuration
+type configView struct {
+	ConfigDir      string `json:"config_dir" yaml:"config_dir"`
+	WranglerConfig string `json:"wrangler_config" yaml:"wrangler_config"`
+	WranglerCmd    string `json:"wrangler_cmd" yaml:"wrangler_cmd"`
+	WranglerVer    string `json:"wrangler_version" yaml:"wrangler_version"`
+	ProjectCmd     string `json:"project_wrangler_cmd" yaml:"project_wrangler_cmd"`
+	SavedAccounts  int    `json:"saved_accounts" yaml:"saved_accounts"`
+	Current        string `json:"current" yaml:"current"`
+	UpdateChannel  string `json:"update_channel" yaml:"update_channel"`
+	UpdateCheck    bool   `json:"update_check" yaml:"update_check"`
+}
+
+func (v configView) Header() []string {
+	return []string{"config_dir", "wrangler_config", "wrangler_cmd", "wrangler_version", "project_wrangler_cmd", "saved_accounts", "current", "update_channel", "update_check"}
+}
+
+func (v configView) Rows() [][]string {
+	return [][]string{{
+		v.ConfigDir,
+		v.WranglerConfig,
+		v.WranglerCmd,
+		v.WranglerVer,
+		v.ProjectCmd,
+		strconv.Itoa(v.SavedAccounts),
+		v.Current,
+		v.UpdateChannel,
+		strconv.FormatBool(v.UpdateCheck),
+	}}
+}
+
 func runConfig(cmd *cobra.Command, args []string) error {
 	db, err := store.LoadDB()
 	if err != nil {
@@ -29,11 +81,85 @@ func runConfig(cmd *cobra.Command, args []string) error {
 	configDir, _ := config.GetConfigDir()
 	wranglerPath, _ := config.GetWranglerConfigPath()
 
+	// Show the configured command the way it will be run
+	wranglerCmd := db.Settings.WranglerCmd
+	var wranglerCmdEnv []string
+	if cmd, err := wrangler.ConfiguredCmd(db.Settings); err == nil {
+		wranglerCmd = cmd.String()
+		wranglerCmdEnv = cmd.Env
+	}
+
+	// A project's own wrangler wins over the configured command
+	var projectCmd string
+	if cwd, err := os.Getwd(); err == nil {
+		if cmd, ok := wrangler.ProjectCmd(cwd); ok {
+			projectCmd = cmd.String()
+		}
+	}
+
+	settingsChanged := false
+	for _, name := range configSettingFlags {
+		if cmd.Flags().Changed(name) {
+			settingsChanged = true
+		}
+	}
+
+	structured, err := configOutput.structured()
+	if err != nil {
+		return err
+	}
+	if structured {
+		if settingsChanged {
+			return fmt.Errorf("settings flags cannot be combined with --output or --template")
+		}
+		return configOutput.print(configView{
+			ConfigDir:      configDir,
+			WranglerConfig: wranglerPath,
+			WranglerCmd:    wranglerCmd,
+			WranglerVer:    wrangler.Version(db),
+			ProjectCmd:     projectCmd,
+			SavedAccounts:  len(db.Accounts),
+			Current:        db.Current,
+			UpdateChannel:  update.NormalizeChannel(db.Settings.UpdateChannel),
+			UpdateCheck:    !db.Settings.DisableUpdateCheck,
+		})
+	}
+
+	wranglerVersion := wrangler.Version(db)
+	if wranglerVersion == "" {
+		wranglerVersion = "unknown"
+	}
+
+	updateCheck := "on"
+	if db.Settings.DisableUpdateCheck {
+		updateCheck = "off"
+	}
+
 	fmt.Println("Current configuration:")
 	fmt.Printf("  Config directory:  %s\n", configDir)
 	fmt.Printf("  Wrangler config:   %s\n", wranglerPath)
-	fmt.Printf("  Wrangler command:  %s\n", db.Settings.WranglerCmd)
+	fmt.Printf("  Wrangler command:  %s\n", wranglerCmd)
+	// Only the names: the values may be credentials
+	for _, env := range wranglerCmdEnv {
+		name, _, _ := strings.Cut(env, "=")
+		fmt.Printf("  Wrangler env:      %s\n", name)
+	}
+	fmt.Printf("  Wrangler version:  %s\n", wranglerVersion)
+	if projectCmd != "" {
+		fmt.Printf("  Project wrangler:  %s (used in this directory)\n", projectCmd)
+	}
 	fmt.Printf("  Saved accounts:    %d\n", len(db.Accounts))
+	fmt.Printf("  Update channel:    %s\n", update.NormalizeChannel(db.Settings.UpdateChannel))
+	fmt.Printf("  Update check:      %s\n", updateCheck)
+
+	if settingsChanged {
+		return applyConfigFlags(cmd, db)
+	}
+
+	// Nothing to edit when we can't ask
+	if !prompt.Interactive() {
+		return nil
+	}
 
 	var edit bool
 	err = huh.NewConfirm().
@@ -49,15 +175,27 @@ func runConfig(cmd *cobra.Command, args []string) error {
 	err = huh.NewInput().
 		Title("Wrangler command:").
 		Value(&newCmd).
-		Placeholder(db.Settings.WranglerCmd).
+		Placeholder(wranglerCmd).
+		Validate(func(line string) error {
+			if line == "" {
+				return nil
+			}
+			_, err := wrangler.ValidateCmdLine(line)
+			return err
+		}).
 		Run()
 
 	if err != nil {
 		return err
 	}
 
-	if newCmd != "" && newCmd != db.Settings.WranglerCmd {
+	if newCmd != "" && newCmd != wranglerCmd {
+		newCmd, err = wrangler.ValidateCmdLine(newCmd)
+		if err != nil {
+			return err
+		}
 		db.Settings.WranglerCmd = newCmd
+		db.Settings.WranglerArgv = nil
 		if err := store.SaveDB(db); err != nil {
 			return fmt.Errorf("failed to save config: %w", err)
 		}
@@ -66,3 +204,31 @@ func runConfig(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+// applyConfigFlags saves the settings given as flags
+func applyConfigFlags(cmd *cobra.Command, db *store.AccountsDB) error {
+	if cmd.Flags().Changed("update-channel") {
+		if configUpdateChannel != update.ChannelStable && configUpdateChannel != update.ChannelBeta {
+			return fmt.Errorf("invalid update channel %q (expected stable or beta)", configUpdateChannel)
+		}
+		db.Settings.UpdateChannel = configUpdateChannel
+	}
+	if cmd.Flags().Changed("update-check") {
+		db.Settings.DisableUpdateCheck = !configUpdateCheck
+	}
+	if cmd.Flags().Changed("wrangler-cmd") {
+		line, err := wrangler.ValidateCmdLine(configWranglerCmd)
+		if err != nil {
+			return fmt.Errorf("--wrangler-cmd: %w", err)
+		}
+		db.Settings.WranglerCmd = line
+		db.Settings.WranglerArgv = nil
+	}
+
+	if err := store.SaveDB(db); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+	fmt.Println("\nConfiguration updated.")
+
+	return nil
+}
@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/fatih/color"
 	"github.com/groo-dev/cl-wrangler/cli/internal/store"
@@ -15,7 +16,10 @@ var currentCmd = &cobra.Command{
 	RunE:  runCurrent,
 }
 
+var currentOutput *outputOptions
+
 func init() {
+	currentOutput = addOutputFlags(currentCmd)
 	rootCmd.AddCommand(currentCmd)
 }
 
@@ -25,6 +29,18 @@ func runCurrent(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to load database: %w", err)
 	}
 
+	structured, err := currentOutput.structured()
+	if err != nil {
+		return err
+	}
+	if structured {
+		acc := db.GetAccount(db.Current)
+		if acc == nil {
+			return fmt.Errorf("no current account set")
+		}
+		return currentOutput.print(newAccountView(db, *acc))
+	}
+
 	if db.Current == "" {
 		fmt.Println("No current account set. Use 'cl add' to save your current wrangler account.")
 		return nil
@@ -40,6 +56,23 @@ func runCurrent(cmd *cobra.Command, args []string) error {
 	fmt.Printf("  Name:  %s\n", acc.Name)
 	fmt.Printf("  Email: %s\n", acc.Email)
 	fmt.Printf("  ID:    %s\n", acc.ID)
+	if acc.AccountName != "" && acc.AccountName != acc.Name {
+		fmt.Printf("  Cloudflare name: %s\n", acc.AccountName)
+	}
+	if len(acc.Aliases) > 0 {
+		fmt.Printf("  Aliases: %s\n", strings.Join(acc.Aliases, ", "))
+	}
+	if acc.Notes != "" {
+		fmt.Printf("  Notes: %s\n", acc.Notes)
+	}
+	if acc.Protected {
+		color.Red("  Protected account")
+	}
+	if db.Revert != nil && db.Revert.From == acc.ID {
+		if previous := db.GetAccount(db.Revert.To); previous != nil {
+			fmt.Printf("  Switching back to %s at %s\n", previous.Name, db.Revert.At.Local().Format("15:04"))
+		}
+	}
 
 	return nil
 }
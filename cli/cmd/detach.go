@@ -0,0 +1,22 @@
+package cmd
+
+import (
+	"os"
+	"os/exec"
+)
+
+// startDetached starts cl with args in its own session, so it outlives this
+// command and the terminal it runs in
+func startDetached(args ...string) error {
+	exe, err := os.Executable()
+	if err != nil {
+		return err
+	}
+
+	child := exec.Command(exe, args...)
+	child.SysProcAttr = detachedProcAttr()
+	if err := child.Start(); err != nil {
+		return err
+	}
+	return child.Process.Release()
+}
@@ -0,0 +1,9 @@
+//go:build !windows
+
+package cmd
+
+import "syscall"
+
+func detachedProcAttr() *syscall.SysProcAttr {
+	return &syscall.SysProcAttr{Setsid: true}
+}
@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/groo-dev/cl-wrangler/cli/internal/shellenv"
+	"github.com/groo-dev/cl-wrangler/cli/internal/store"
+	"github.com/spf13/cobra"
+)
+
+var envCmd = &cobra.Command{
+	Use:   "env [account-name-or-id]",
+	Short: "Print an account's credentials as environment variables",
+	Long: `Prints CLOUDFLARE_ACCOUNT_ID and CLOUDFLARE_API_TOKEN for the current or given account.
+Accounts using a global API key get CLOUDFLARE_API_KEY and CLOUDFLARE_EMAIL instead of a token.
+Expired OAuth tokens are refreshed first. Use --no-secrets to print only the account ID.
+
+Formats: bash, zsh, fish, powershell, dotenv, github ($GITHUB_ENV) and json.`,
+	Example: `  eval "$(cl env)"
+  cl env acme --format fish | source
+  cl env acme --format github >> "$GITHUB_ENV"
+  cl env --format dotenv > .env`,
+	Args:              cobra.MaximumNArgs(1),
+	RunE:              runEnv,
+	ValidArgsFunction: completeAccountNames,
+}
+
+var (
+	envFormat    string
+	envNoSecrets bool
+)
+
+func init() {
+	envCmd.Flags().StringVarP(&envFormat, "format", "f", string(shellenv.FormatBash), "Format: bash, zsh, fish, powershell, dotenv, github, json")
+	envCmd.Flags().BoolVar(&envNoSecrets, "no-secrets", false, "Leave out tokens and keys")
+	rootCmd.AddCommand(envCmd)
+}
+
+func runEnv(cmd *cobra.Command, args []string) error {
+	format, err := shellenv.ParseFormat(envFormat)
+	if err != nil {
+		return err
+	}
+
+	db, err := store.LoadDB()
+	if err != nil {
+		return fmt.Errorf("failed to load database: %w", err)
+	}
+
+	var acc *store.Account
+	if len(args) > 0 {
+		targetID, err := findAccount(db, strings.Join(args, " "))
+		if err != nil {
+			return err
+		}
+		acc = db.GetAccount(targetID)
+	} else {
+		if db.Current == "" {
+			return fmt.Errorf("no current account set")
+		}
+		acc = db.GetAccount(db.Current)
+		if acc == nil {
+			return fmt.Errorf("current account not found in database")
+		}
+	}
+	if acc.LoggedOut {
+		return fmt.Errorf("%s is logged out", acc.Name)
+	}
+
+	vars, err := accountEnv(db, acc, !envNoSecrets)
+	if err != nil {
+		return err
+	}
+	return shellenv.Write(os.Stdout, format, vars)
+}
+
+// accountEnv returns the environment variables that authenticate wrangler,
+// Terraform and the Cloudflare API as acc
+func accountEnv(db *store.AccountsDB, acc *store.Account, secrets bool) ([]shellenv.Var, error) {
+	vars := []shellenv.Var{{Name: "CLOUDFLARE_ACCOUNT_ID", Value: acc.ID}}
+	if !secrets {
+		return vars, nil
+	}
+
+	info, err := freshTokenInfo(db, acc)
+	if err != nil {
+		return nil, err
+	}
+
+	switch info.Type {
+	case store.TokenTypeOAuth, store.TokenTypeAPIToken:
+		vars = append(vars, shellenv.Var{Name: "CLOUDFLARE_API_TOKEN", Value: info.Token})
+	case store.TokenTypeAPIKey:
+		vars = append(vars,
+			shellenv.Var{Name: "CLOUDFLARE_API_KEY", Value: info.Token},
+			shellenv.Var{Name: "CLOUDFLARE_EMAIL", Value: acc.Email},
+		)
+	default:
+		return nil, fmt.Errorf("no token found in the config of %s", acc.Name)
+	}
+
+	return vars, nil
+}
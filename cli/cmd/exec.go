@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+
+	"github.com/groo-dev/cl-wrangler/cli/internal/store"
+	"github.com/groo-dev/cl-wrangler/cli/internal/wrangler"
+	"github.com/spf13/cobra"
+)
+
+var execCmd = &cobra.Command{
+	Use:   "exec <account-name-or-id> [--] <wrangler-args...>",
+	Short: "Run wrangler as a saved account",
+	Long: `Runs wrangler with a saved account's credentials, using its pinned wrangler if it has one.
+The credentials are passed in CLOUDFLARE_API_TOKEN (CLOUDFLARE_API_KEY and
+CLOUDFLARE_EMAIL for API keys) and CLOUDFLARE_ACCOUNT_ID, so the live wrangler
+config is neither used nor changed. An expired OAuth token is refreshed first.
+Protected accounts need their name typed, or --confirm=<name> before the account.`,
+	Example: `  cl exec acme deploy --env production
+  cl exec acme -- whoami
+  cl exec --confirm=acme-prod acme-prod deploy`,
+	Args:              cobra.MinimumNArgs(2),
+	RunE:              runExec,
+	ValidArgsFunction: completeAccountNames,
+}
+
+var execConfirm string
+
+func init() {
+	execCmd.Flags().StringVar(&execConfirm, "confirm", "", "Name of the protected account, instead of typing it")
+	// Everything after the account belongs to wrangler
+	execCmd.Flags().SetInterspersed(false)
+	rootCmd.AddCommand(execCmd)
+}
+
+func runExec(cmd *cobra.Command, args []string) error {
+	db, err := store.LoadDB()
+	if err != nil {
+		return fmt.Errorf("failed to load database: %w", err)
+	}
+
+	targetID, err := findAccount(db, args[0])
+	if err != nil {
+		return err
+	}
+	acc := db.GetAccount(targetID)
+
+	wranglerArgs := args[1:]
+	if wranglerArgs[0] == "--" {
+		wranglerArgs = wranglerArgs[1:]
+	}
+	if len(wranglerArgs) == 0 {
+		return fmt.Errorf("no wrangler arguments given")
+	}
+
+	if acc.LoggedOut {
+		return fmt.Errorf("%s is logged out; log in again with 'cl switch %s'", acc.Name, acc.Name)
+	}
+	if err := confirmProtected(acc, execConfirm); err != nil {
+		return err
+	}
+
+	wranglerCmd, err := wrangler.EnsureProfileCmd(db, acc)
+	if err != nil {
+		return fmt.Errorf("failed to find wrangler: %w", err)
+	}
+	token, err := freshTokenInfo(db, acc)
+	if err != nil {
+		return err
+	}
+	wranglerCmd.Env = append(wranglerCmd.Env, credentialEnv(acc, token)...)
+
+	runErr := wrangler.Exec(cmd.Context(), wranglerCmd, wranglerArgs...)
+
+	// Pass wrangler's exit code through
+	var exitErr *exec.ExitError
+	if errors.As(runErr, &exitErr) {
+		os.Exit(exitErr.ExitCode())
+	}
+	return runErr
+}
+
+// credentialEnv returns the environment that makes wrangler act as acc with
+// the given credentials, whatever its live config holds
+func credentialEnv(acc *store.Account, token *store.TokenInfo) []string {
+	env := []string{"CLOUDFLARE_ACCOUNT_ID=" + acc.ID}
+	if token.Type == store.TokenTypeAPIKey {
+		return append(env, "CLOUDFLARE_API_KEY="+token.Token, "CLOUDFLARE_EMAIL="+acc.Email)
+	}
+	return append(env, "CLOUDFLARE_API_TOKEN="+token.Token)
+}
@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/charmbracelet/huh"
+	"github.com/fatih/color"
+	"github.com/groo-dev/cl-wrangler/cli/internal/bundle"
+	"github.com/groo-dev/cl-wrangler/cli/internal/prompt"
+	"github.com/groo-dev/cl-wrangler/cli/internal/store"
+	"github.com/spf13/cobra"
+)
+
+// defaultPassphraseEnv holds the bundle passphrase unless --passphrase-env says otherwise
+const defaultPassphraseEnv = "CL_BUNDLE_PASSPHRASE"
+
+var exportCmd = &cobra.Command{
+	Use:   "export --profile <account-name-or-id>",
+	Short: "Export a saved account",
+	Long: `Exports one saved account with its wrangler config.
+With --for-ci, prints a single base64 line to store as a CI secret and restore with 'cl ci-restore'.
+With --encrypt, the value is encrypted with the passphrase in $CL_BUNDLE_PASSPHRASE
+(or the variable named by --passphrase-env), or one you type.
+The output contains credentials; treat it as a secret.`,
+	Example: `  cl export --profile acme --for-ci | gh secret set CL_PROFILE_BUNDLE
+  CL_BUNDLE_PASSPHRASE=... cl export --profile acme --for-ci --encrypt`,
+	Args: cobra.NoArgs,
+	RunE: runExport,
+}
+
+var (
+	exportProfile       string
+	exportForCI         bool
+	exportEncrypt       bool
+	exportPassphraseEnv string
+)
+
+func init() {
+	exportCmd.Flags().StringVar(&exportProfile, "profile", "", "Account to export (default: current)")
+	exportCmd.Flags().BoolVar(&exportForCI, "for-ci", false, "Print a base64 value for 'cl ci-restore'")
+	exportCmd.Flags().BoolVar(&exportEncrypt, "encrypt", false, "Encrypt the value with a passphrase (requires --for-ci)")
+	exportCmd.Flags().StringVar(&exportPassphraseEnv, "passphrase-env", defaultPassphraseEnv, "Environment variable holding the passphrase")
+	exportCmd.RegisterFlagCompletionFunc("profile", completeAccountNames)
+	rootCmd.AddCommand(exportCmd)
+}
+
+func runExport(cmd *cobra.Command, args []string) error {
+	if exportEncrypt && !exportForCI {
+		return fmt.Errorf("--encrypt requires --for-ci")
+	}
+
+	db, err := store.LoadDB()
+	if err != nil {
+		return fmt.Errorf("failed to load database: %w", err)
+	}
+
+	targetID := db.Current
+	if exportProfile != "" {
+		targetID, err = findAccount(db, exportProfile)
+		if err != nil {
+			return err
+		}
+	}
+	acc := db.GetAccount(targetID)
+	if acc == nil {
+		return fmt.Errorf("no current account set; pass --profile")
+	}
+	if acc.LoggedOut {
+		return fmt.Errorf("%s is logged out", acc.Name)
+	}
+
+	// Export a token that is valid now
+	info, err := freshTokenInfo(db, acc)
+	if err != nil {
+		return err
+	}
+	if info.Type == store.TokenTypeOAuth {
+		color.New(color.FgYellow).Fprintf(os.Stderr, "Warning: %s uses an OAuth login. Refreshing it elsewhere can invalidate this copy; prefer an API token for CI.\n", acc.Name)
+	}
+
+	path, err := db.AccountConfigPath(acc.ID)
+	if err != nil {
+		return err
+	}
+	config, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config of %s: %w", acc.Name, err)
+	}
+	b := bundle.New(*acc, config)
+
+	if !exportForCI {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(b)
+	}
+
+	var passphrase string
+	if exportEncrypt {
+		passphrase, err = exportPassphrase()
+		if err != nil {
+			return err
+		}
+	}
+
+	value, err := bundle.Encode(b, passphrase)
+	if err != nil {
+		return fmt.Errorf("failed to encode bundle: %w", err)
+	}
+	fmt.Println(value)
+
+	return nil
+}
+
+// exportPassphrase reads the passphrase from the environment or asks for it
+func exportPassphrase() (string, error) {
+	if passphrase := os.Getenv(exportPassphraseEnv); passphrase != "" {
+		return passphrase, nil
+	}
+	if err := prompt.Require("set $" + exportPassphraseEnv); err != nil {
+		return "", err
+	}
+
+	// Prompt on stderr so stdout can be piped into a secret store
+	var passphrase string
+	input := huh.NewInput().
+		Title("Passphrase:").
+		EchoMode(huh.EchoModePassword).
+		Value(&passphrase).
+		Validate(func(s string) error {
+			if s == "" {
+				return fmt.Errorf("passphrase cannot be empty")
+			}
+			return nil
+		})
+	err := huh.NewForm(huh.NewGroup(input)).WithOutput(os.Stderr).Run()
+
+	return passphrase, err
+}
@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/fatih/color"
+	"github.com/groo-dev/cl-wrangler/cli/internal/store"
+	"github.com/spf13/cobra"
+)
+
+var favoriteCmd = &cobra.Command{
+	Use:     "favorite <account-name-or-id>",
+	Aliases: []string{"fav"},
+	Short:   "Pin an account to the top of lists",
+	Long: `Marks a saved account as a favorite. Favorites are listed first by
+'cl list' and the switch menu. Use --clear to unmark it.`,
+	Args:              cobra.ExactArgs(1),
+	RunE:              runFavorite,
+	ValidArgsFunction: completeAccountNames,
+}
+
+var favoriteClear bool
+
+func init() {
+	favoriteCmd.Flags().BoolVar(&favoriteClear, "clear", false, "Remove the account from favorites")
+	rootCmd.AddCommand(favoriteCmd)
+}
+
+func runFavorite(cmd *cobra.Command, args []string) error {
+	db, err := store.LoadDB()
+	if err != nil {
+		return fmt.Errorf("failed to load database: %w", err)
+	}
+
+	targetID, err := findAccount(db, args[0])
+	if err != nil {
+		return err
+	}
+	acc := db.GetAccount(targetID)
+
+	acc.Favorite = !favoriteClear
+	db.AddAccount(*acc)
+	if err := store.SaveDB(db); err != nil {
+		return fmt.Errorf("failed to save database: %w", err)
+	}
+
+	if favoriteClear {
+		color.Green("✓ Removed %s from favorites", acc.Name)
+	} else {
+		color.Green("✓ Added %s to favorites", acc.Name)
+	}
+
+	return nil
+}
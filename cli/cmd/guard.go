@@ -0,0 +1,320 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/groo-dev/cl-wrangler/cli/internal/match"
+	"github.com/groo-dev/cl-wrangler/cli/internal/project"
+	"github.com/groo-dev/cl-wrangler/cli/internal/store"
+	"github.com/spf13/cobra"
+)
+
+// Exit codes of 'cl guard', one per failing check; the first failing check decides
+const (
+	guardExitNoAccount     = 2 // no active profile, and it decides the account
+	guardExitProfile       = 3 // active profile is another account, and it decides the account
+	guardExitEnv           = 4 // CLOUDFLARE_ACCOUNT_ID names another account, or env credentials name none
+	guardExitProject       = 5 // project account_id names another account
+	guardExitNoProjectFile = 6 // --project but no wrangler config found
+	guardExitBadExpect     = 7 // --expect-account doesn't name one account
+	guardExitBadProject    = 8 // the project's wrangler config can't be read
+)
+
+// Sources of the account IDs 'cl guard' compares
+const (
+	guardSourceProfile = "active_profile"
+	guardSourceEnv     = "env"
+	guardSourceProject = "project"
+)
+
+// Check statuses
+const (
+	guardMatch    = "match"
+	guardMismatch = "mismatch"
+	guardUnset    = "unset"
+	// the source is set but wrangler won't use it to pick the account
+	guardOverridden = "overridden"
+	// wrangler's account can't be told without asking Cloudflare
+	guardUnknown = "unknown"
+)
+
+var guardCmd = &cobra.Command{
+	Use:   "guard --expect-account <id-or-name>",
+	Short: "Fail unless wrangler would use the expected account",
+	Long: `Checks that the account wrangler will use is the expected one: CLOUDFLARE_ACCOUNT_ID
+if set, and with --project the account_id in the project's wrangler.toml, wrangler.json
+or wrangler.jsonc. The active profile only counts when no CLOUDFLARE_API_TOKEN,
+CLOUDFLARE_API_KEY or CLOUDFLARE_ACCOUNT_ID overrides it. Meant for CI steps and
+git hooks before a deploy.
+
+Exit codes (the first failing check decides):
+  0  all checks match
+  2  no active profile, and no environment variable names the account
+  3  the active profile is another account, and no environment variable overrides it
+  4  CLOUDFLARE_ACCOUNT_ID is another account, or API credentials in the
+     environment come without an account ID to check
+  5  the project's account_id is another account
+  6  --project was given but no wrangler config was found
+  7  --expect-account doesn't name a single account
+  8  the project's wrangler config can't be read`,
+	Example: `  cl guard --expect-account acme-prod --project
+  cl guard --expect-account 0123456789abcdef0123456789abcdef --project --env production -o json`,
+	Args: cobra.NoArgs,
+	RunE: runGuard,
+}
+
+var (
+	guardExpect  string
+	guardProject bool
+	guardConfig  string
+	guardEnv     string
+	guardOutput  *outputOptions
+)
+
+// accountIDRegex matches a Cloudflare account ID
+var accountIDRegex = regexp.MustCompile(`^[0-9a-f]{32}$`)
+
+func init() {
+	guardCmd.Flags().StringVar(&guardExpect, "expect-account", "", "Expected account: a saved account's name, alias or ID, or any account ID")
+	guardCmd.Flags().BoolVar(&guardProject, "project", false, "Also check account_id in the project's wrangler config")
+	guardCmd.Flags().StringVarP(&guardConfig, "config", "c", "", "Wrangler config file to check (implies --project)")
+	guardCmd.Flags().StringVarP(&guardEnv, "env", "e", "", "Wrangler environment whose account_id to check")
+	guardCmd.MarkFlagRequired("expect-account")
+	guardCmd.RegisterFlagCompletionFunc("expect-account", completeAccountNames)
+	guardOutput = addOutputFlags(guardCmd)
+	rootCmd.AddCommand(guardCmd)
+}
+
+// guardCheck is one compared source in the machine-readable guard report
+type guardCheck struct {
+	Source    string `json:"source" yaml:"source"`
+	AccountID string `json:"account_id" yaml:"account_id"`
+	Name      string `json:"name" yaml:"name"`
+	Status    string `json:"status" yaml:"status"`
+	Detail    string `json:"detail" yaml:"detail"`
+}
+
+// guardReport is the machine-readable result of 'cl guard'
+type guardReport struct {
+	OK         bool         `json:"ok" yaml:"ok"`
+	ExitCode   int          `json:"exit_code" yaml:"exit_code"`
+	Error      string       `json:"error,omitempty" yaml:"error,omitempty"`
+	ExpectedID string       `json:"expected_id" yaml:"expected_id"`
+	Expected   string       `json:"expected" yaml:"expected"`
+	Checks     []guardCheck `json:"checks" yaml:"checks"`
+}
+
+func (r guardReport) Header() []string {
+	return []string{"source", "account_id", "name", "status", "detail"}
+}
+
+func (r guardReport) Rows() [][]string {
+	rows := make([][]string, 0, len(r.Checks))
+	for _, c := range r.Checks {
+		rows = append(rows, []string{c.Source, c.AccountID, c.Name, c.Status, c.Detail})
+	}
+	return rows
+}
+
+func runGuard(cmd *cobra.Command, args []string) error {
+	structured, err := guardOutput.structured()
+	if err != nil {
+		return err
+	}
+
+	db, err := store.LoadDB()
+	if err != nil {
+		return fmt.Errorf("failed to load database: %w", err)
+	}
+
+	report := guardReport{OK: true, Expected: strings.TrimSpace(guardExpect), Checks: []guardCheck{}}
+	fail := func(code int) {
+		if report.OK {
+			report.OK = false
+			report.ExitCode = code
+		}
+	}
+
+	expectedID, err := resolveExpectedAccount(db, guardExpect)
+	if err != nil {
+		return guardError(report, structured, guardExitBadExpect, err)
+	}
+	report.ExpectedID = expectedID
+	report.Expected = accountLabel(db, expectedID)
+
+	check := func(source, id, detail string, code int) {
+		c := guardCheck{Source: source, AccountID: id, Name: accountLabel(db, id), Status: guardMatch, Detail: detail}
+		switch {
+		case id == "":
+			c.Status = guardUnset
+			c.Name = ""
+		case id != expectedID:
+			c.Status = guardMismatch
+			fail(code)
+		}
+		report.Checks = append(report.Checks, c)
+	}
+
+	// Read the project first: with env credentials its account_id may be all
+	// that tells which account wrangler will use
+	var configPath, projectID string
+	if guardProject || guardConfig != "" {
+		configPath = guardConfig
+		if configPath == "" {
+			cwd, err := os.Getwd()
+			if err != nil {
+				return err
+			}
+			configPath, _ = project.FindConfig(cwd)
+		}
+		if configPath != "" {
+			projectID, err = project.AccountID(configPath, guardEnv)
+			if err != nil {
+				return guardError(report, structured, guardExitBadProject, err)
+			}
+		}
+	}
+
+	// Wrangler takes credentials from the environment over the profile, and
+	// the account from CLOUDFLARE_ACCOUNT_ID over the profile's
+	envID := os.Getenv("CLOUDFLARE_ACCOUNT_ID")
+	envCreds := envCredentialsVar()
+	switch {
+	case envCreds != "":
+		report.Checks = append(report.Checks, guardCheck{Source: guardSourceProfile, AccountID: db.Current,
+			Name: accountLabel(db, db.Current), Status: guardOverridden, Detail: "credentials from " + envCreds})
+	case envID != "":
+		report.Checks = append(report.Checks, guardCheck{Source: guardSourceProfile, AccountID: db.Current,
+			Name: accountLabel(db, db.Current), Status: guardOverridden, Detail: "account from CLOUDFLARE_ACCOUNT_ID"})
+	default:
+		check(guardSourceProfile, db.Current, "", guardExitProfile)
+		if db.Current == "" {
+			fail(guardExitNoAccount)
+		}
+	}
+
+	switch {
+	case envID != "":
+		check(guardSourceEnv, envID, "CLOUDFLARE_ACCOUNT_ID", guardExitEnv)
+	case envCreds != "" && projectID == "":
+		report.Checks = append(report.Checks, guardCheck{Source: guardSourceEnv, Status: guardUnknown,
+			Detail: envCreds + " without CLOUDFLARE_ACCOUNT_ID"})
+		fail(guardExitEnv)
+	}
+
+	switch {
+	case configPath != "":
+		check(guardSourceProject, projectID, configPath, guardExitProject)
+	case guardProject || guardConfig != "":
+		report.Checks = append(report.Checks, guardCheck{Source: guardSourceProject, Status: guardUnset, Detail: "no wrangler config found"})
+		fail(guardExitNoProjectFile)
+	}
+
+	if structured {
+		if err := guardOutput.print(report); err != nil {
+			return err
+		}
+	} else {
+		printGuardReport(report)
+	}
+
+	if !report.OK {
+		os.Exit(report.ExitCode)
+	}
+	return nil
+}
+
+// guardError ends 'cl guard' with code when it can't run its checks,
+// still printing a report for -o json
+func guardError(report guardReport, structured bool, code int, err error) error {
+	if !structured {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(code)
+	}
+	report.OK = false
+	report.ExitCode = code
+	report.Error = err.Error()
+	if err := guardOutput.print(report); err != nil {
+		return err
+	}
+	os.Exit(code)
+	return nil
+}
+
+// envCredentialsVar returns the environment variable wrangler takes
+// credentials from instead of the profile, or "" if none is set
+func envCredentialsVar() string {
+	for _, name := range []string{"CLOUDFLARE_API_TOKEN", "CLOUDFLARE_API_KEY"} {
+		if os.Getenv(name) != "" {
+			return name
+		}
+	}
+	return ""
+}
+
+// resolveExpectedAccount turns --expect-account into an account ID. Saved
+// accounts match by exact name, alias or ID; any other account ID is taken as is.
+func resolveExpectedAccount(db *store.AccountsDB, query string) (string, error) {
+	query = strings.TrimSpace(query)
+	best := match.Best(match.Find(db.Accounts, query, true))
+	switch {
+	case len(best) == 1:
+		return best[0].Account.ID, nil
+	case len(best) > 1:
+		return "", fmt.Errorf("'%s' names several saved accounts; use an account ID", query)
+	case accountIDRegex.MatchString(query):
+		return query, nil
+	}
+	return "", fmt.Errorf("no saved account named '%s' and not an account ID", query)
+}
+
+// accountLabel returns the saved name of an account ID, or "" if it isn't saved
+func accountLabel(db *store.AccountsDB, id string) string {
+	if acc := db.GetAccount(id); acc != nil {
+		return acc.Name
+	}
+	return ""
+}
+
+func printGuardReport(report guardReport) {
+	for _, c := range report.Checks {
+		label := c.AccountID
+		if c.Name != "" {
+			label = fmt.Sprintf("%s (%s)", c.Name, c.AccountID)
+		}
+		source := c.Source
+		if c.Detail != "" {
+			source = fmt.Sprintf("%s %s", c.Source, c.Detail)
+		}
+
+		switch c.Status {
+		case guardMatch:
+			color.Green("✓ %s: %s", source, label)
+		case guardMismatch:
+			color.Red("✗ %s: %s", source, label)
+		case guardOverridden:
+			if label == "" {
+				label = "none"
+			}
+			fmt.Printf("- %s: %s, not used\n", source, label)
+		case guardUnknown:
+			color.Red("✗ %s: account unknown; set CLOUDFLARE_ACCOUNT_ID", source)
+		default:
+			fmt.Printf("- %s: not set\n", source)
+		}
+	}
+
+	expected := report.ExpectedID
+	if report.Expected != "" {
+		expected = fmt.Sprintf("%s (%s)", report.Expected, report.ExpectedID)
+	}
+	if report.OK {
+		color.Green("✓ Wrangler will use %s", expected)
+	} else {
+		color.Red("✗ Expected %s", expected)
+	}
+}
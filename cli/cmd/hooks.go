@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/fatih/color"
+	"github.com/groo-dev/cl-wrangler/cli/internal/hooks"
+	"github.com/groo-dev/cl-wrangler/cli/internal/store"
+)
+
+// runPreSwitchHooks runs pre-switch hooks if the current account is about to change.
+// A failing hook vetoes the change.
+func runPreSwitchHooks(db *store.AccountsDB, command string, newAcc *store.Account) error {
+	event, changed := switchEvent(hooks.PreSwitch, command, db.GetAccount(db.Current), newAcc)
+	if !changed {
+		return nil
+	}
+	if err := hooks.Run(db, event); err != nil {
+		return fmt.Errorf("switch aborted: %w", err)
+	}
+	return nil
+}
+
+// runPostSwitchHooks runs post-switch hooks if the current account changed.
+// Failures are reported but don't undo the switch.
+func runPostSwitchHooks(db *store.AccountsDB, command string, oldAcc *store.Account) {
+	event, changed := switchEvent(hooks.PostSwitch, command, oldAcc, db.GetAccount(db.Current))
+	if !changed {
+		return
+	}
+	if err := hooks.Run(db, event); err != nil {
+		color.Yellow("Warning: %v", err)
+	}
+}
+
+func switchEvent(hook, command string, oldAcc, newAcc *store.Account) (hooks.Event, bool) {
+	var oldID, newID string
+	if oldAcc != nil {
+		oldID = oldAcc.ID
+	}
+	if newAcc != nil {
+		newID = newAcc.ID
+	}
+
+	event := hooks.Event{Hook: hook, Command: command, Old: oldAcc, New: newAcc}
+	return event, oldID != newID
+}
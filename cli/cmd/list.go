@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/fatih/color"
 	"github.com/groo-dev/cl-wrangler/cli/internal/store"
@@ -12,11 +13,20 @@ var listCmd = &cobra.Command{
 	Use:     "list",
 	Aliases: []string{"ls"},
 	Short:   "List saved accounts",
-	Long:    `Lists all saved Cloudflare/Wrangler accounts.`,
-	RunE:    runList,
+	Long: `Lists all saved Cloudflare/Wrangler accounts.
+Favorites come first, then accounts grouped by their first tag.`,
+	RunE: runList,
 }
 
+var (
+	listOutput *outputOptions
+	listTags   []string
+)
+
 func init() {
+	listOutput = addOutputFlags(listCmd)
+	listCmd.Flags().StringSliceVar(&listTags, "tag", nil, "Only list accounts with this tag (repeatable)")
+	listCmd.RegisterFlagCompletionFunc("tag", completeTags)
 	rootCmd.AddCommand(listCmd)
 }
 
@@ -26,22 +36,77 @@ func runList(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to load database: %w", err)
 	}
 
+	structured, err := listOutput.structured()
+	if err != nil {
+		return err
+	}
+	accounts := store.FilterByTags(db.Accounts, listTags)
+	if structured {
+		views := accountListView{}
+		for _, acc := range accounts {
+			views = append(views, newAccountView(db, acc))
+		}
+		return listOutput.print(views)
+	}
+
 	if len(db.Accounts) == 0 {
 		fmt.Println("No accounts saved. Use 'cl add' to save your current wrangler account.")
 		return nil
 	}
+	if len(accounts) == 0 {
+		fmt.Printf("No accounts tagged %s.\n", strings.Join(listTags, " or "))
+		return nil
+	}
 
 	green := color.New(color.FgGreen)
+	heading := color.New(color.Bold)
+
+	groups := store.GroupAccounts(accounts)
+	for i, group := range groups {
+		if len(groups) > 1 || group.Name != "" {
+			if i > 0 {
+				fmt.Println()
+			}
+			heading.Println(groupHeading(group))
+		}
 
-	for _, acc := range db.Accounts {
-		if acc.ID == db.Current {
-			green.Printf("→ %s (%s)\n", acc.Name, acc.Email)
-			green.Printf("  %s\n", acc.ID)
-		} else {
-			fmt.Printf("  %s (%s)\n", acc.Name, acc.Email)
-			fmt.Printf("  %s\n", acc.ID)
+		for _, acc := range group.Accounts {
+			printer := color.New()
+			marker := " "
+			if acc.ID == db.Current {
+				printer = green
+				marker = "→"
+			}
+			status := ""
+			if acc.LoggedOut {
+				status = " [logged out]"
+			}
+			if acc.Protected {
+				status += " [protected]"
+			}
+			printer.Printf("%s %s", marker, accountName(acc))
+			printer.Printf(" (%s)%s\n", acc.Email, status)
+			printer.Printf("  %s\n", acc.ID)
+			if len(acc.Aliases) > 0 {
+				printer.Printf("  aliases: %s\n", strings.Join(acc.Aliases, ", "))
+			}
+			if len(acc.Tags) > 0 {
+				printer.Printf("  tags: %s\n", strings.Join(acc.Tags, ", "))
+			}
 		}
 	}
 
 	return nil
 }
+
+// groupHeading labels a group of accounts in lists and menus
+func groupHeading(group store.AccountGroup) string {
+	switch group.Name {
+	case store.FavoritesGroup:
+		return "★ Favorites"
+	case "":
+		return "Untagged"
+	default:
+		return "# " + group.Name
+	}
+}
@@ -0,0 +1,201 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/groo-dev/cl-wrangler/cli/internal/audit"
+	"github.com/groo-dev/cl-wrangler/cli/internal/store"
+	"github.com/spf13/cobra"
+)
+
+var logCmd = &cobra.Command{
+	Use:   "log",
+	Short: "Show the audit log of account operations",
+	Long: `Shows the add, switch, remove, logout and refresh operations recorded in
+the audit log, oldest first. Events are hash-chained; use --verify to detect tampering.
+--since and --until accept RFC 3339 times, dates (2006-01-02) or durations ago (24h, 7d).`,
+	RunE: runLog,
+}
+
+var (
+	logAccount string
+	logSince   string
+	logUntil   string
+	logVerify  bool
+	logOutput  *outputOptions
+)
+
+func init() {
+	logCmd.Flags().StringVar(&logAccount, "account", "", "Only show events involving this account")
+	logCmd.Flags().StringVar(&logSince, "since", "", "Only show events at or after this time")
+	logCmd.Flags().StringVar(&logUntil, "until", "", "Only show events before this time")
+	logCmd.Flags().BoolVar(&logVerify, "verify", false, "Verify the hash chain of the whole log")
+	logCmd.RegisterFlagCompletionFunc("account", completeAccountNames)
+	logOutput = addOutputFlags(logCmd)
+	rootCmd.AddCommand(logCmd)
+}
+
+// eventListView is a list of audit events that can also be written as TSV
+type eventListView []audit.Event
+
+func (l eventListView) Header() []string {
+	return []string{"time", "action", "account_id", "account_name", "from_id", "to_id", "cwd", "hostname", "user", "version", "hash"}
+}
+
+func (l eventListView) Rows() [][]string {
+	rows := make([][]string, 0, len(l))
+	for _, e := range l {
+		rows = append(rows, []string{
+			e.Time.Format(time.RFC3339),
+			e.Action,
+			e.AccountID,
+			e.AccountName,
+			e.FromID,
+			e.ToID,
+			e.Cwd,
+			e.Hostname,
+			e.User,
+			e.Version,
+			e.Hash,
+		})
+	}
+	return rows
+}
+
+func runLog(cmd *cobra.Command, args []string) error {
+	events, skipped, err := audit.ReadLog()
+	if err != nil {
+		return fmt.Errorf("failed to read audit log: %w", err)
+	}
+	if len(skipped) > 0 && !logVerify {
+		color.New(color.FgYellow).Fprintf(os.Stderr, "Warning: skipped %d unreadable line(s) in the audit log; run 'cl log --verify'\n", len(skipped))
+	}
+
+	if logVerify {
+		if len(skipped) > 0 {
+			return fmt.Errorf("audit log verification failed: unreadable line(s) %v", skipped)
+		}
+		if err := audit.Verify(events); err != nil {
+			return fmt.Errorf("audit log verification failed: %w", err)
+		}
+		color.Green("✓ Audit log intact (%d events)", len(events))
+		return nil
+	}
+
+	since, err := parseTimeFlag(logSince)
+	if err != nil {
+		return fmt.Errorf("invalid --since: %w", err)
+	}
+	until, err := parseTimeFlag(logUntil)
+	if err != nil {
+		return fmt.Errorf("invalid --until: %w", err)
+	}
+
+	accountID := ""
+	if logAccount != "" {
+		accountID = logAccount
+		// Resolve names and aliases of saved accounts; removed accounts can still be given by ID
+		if db, err := store.LoadDB(); err == nil {
+			if id, err := findAccount(db, logAccount); err == nil {
+				accountID = id
+			}
+		}
+	}
+
+	filtered := eventListView{}
+	for _, e := range events {
+		if accountID != "" && !e.Involves(accountID) {
+			continue
+		}
+		if !since.IsZero() && e.Time.Before(since) {
+			continue
+		}
+		if !until.IsZero() && !e.Time.Before(until) {
+			continue
+		}
+		filtered = append(filtered, e)
+	}
+
+	structured, err := logOutput.structured()
+	if err != nil {
+		return err
+	}
+	if structured {
+		return logOutput.print(filtered)
+	}
+
+	if len(filtered) == 0 {
+		fmt.Println("No events found.")
+		return nil
+	}
+
+	for _, e := range filtered {
+		fmt.Printf("%s  %-7s  %s  %s\n",
+			e.Time.Local().Format("2006-01-02 15:04:05"),
+			e.Action,
+			describeEvent(e),
+			color.New(color.Faint).Sprintf("%s@%s %s", e.User, e.Hostname, e.Cwd),
+		)
+	}
+
+	return nil
+}
+
+func describeEvent(e audit.Event) string {
+	if e.Action == audit.ActionSwitch {
+		return fmt.Sprintf("%s → %s", orNone(e.FromName), orNone(e.ToName))
+	}
+	return e.AccountName
+}
+
+func orNone(name string) string {
+	if name == "" {
+		return "(none)"
+	}
+	return name
+}
+
+// parseTimeFlag parses an RFC 3339 time, a date, or a duration ago such as 24h or 7d
+func parseTimeFlag(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		return t, nil
+	}
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		if n, err := strconv.Atoi(days); err == nil {
+			return time.Now().AddDate(0, 0, -n), nil
+		}
+	}
+	if d, err := time.ParseDuration(value); err == nil {
+		return time.Now().Add(-d), nil
+	}
+	return time.Time{}, fmt.Errorf("expected a time, date or duration: %s", value)
+}
+
+// recordEvent appends an operation to the audit log. Failures are reported but not fatal.
+func recordEvent(action string, acc, from, to *store.Account) {
+	e := audit.Event{Action: action, Version: Version}
+	if acc != nil {
+		e.AccountID, e.AccountName = acc.ID, acc.Name
+	}
+	if from != nil {
+		e.FromID, e.FromName = from.ID, from.Name
+	}
+	if to != nil {
+		e.ToID, e.ToName = to.ID, to.Name
+	}
+
+	if err := audit.Append(e); err != nil {
+		color.New(color.FgYellow).Fprintf(os.Stderr, "Warning: failed to write audit log: %v\n", err)
+	}
+}
@@ -1,23 +1,39 @@
 package cmd
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"os"
+	"strings"
 
-	"github.com/charmbracelet/huh"
 	"github.com/fatih/color"
+	"github.com/groo-dev/cl-wrangler/cli/internal/audit"
+	"github.com/groo-dev/cl-wrangler/cli/internal/prompt"
 	"github.com/groo-dev/cl-wrangler/cli/internal/store"
 	"github.com/groo-dev/cl-wrangler/cli/internal/wrangler"
 	"github.com/spf13/cobra"
 )
 
 var logoutCmd = &cobra.Command{
-	Use:   "logout",
-	Short: "Logout from current account",
-	Long:  `Runs wrangler logout and removes the current account from saved accounts.`,
-	RunE:  runLogout,
+	Use:   "logout [account-name-or-id]",
+	Short: "Logout from an account",
+	Long: `Revokes the token of a saved account and removes it from saved accounts.
+Without an argument, logs out of the current account with wrangler logout.
+Other accounts are logged out by revoking their token directly, so the active account is not disturbed.
+Use --keep to keep the profile, marked as logged out, and --all to log out of every account.`,
+	RunE:              runLogout,
+	ValidArgsFunction: completeAccountNames,
 }
 
+var (
+	logoutAll  bool
+	logoutKeep bool
+)
+
 func init() {
+	logoutCmd.Flags().BoolVar(&logoutAll, "all", false, "Log out of all saved accounts")
+	logoutCmd.Flags().BoolVar(&logoutKeep, "keep", false, "Keep the profile, marked as logged out")
 	rootCmd.AddCommand(logoutCmd)
 }
 
@@ -27,60 +43,207 @@ func runLogout(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to load database: %w", err)
 	}
 
-	if db.Current == "" {
-		return fmt.Errorf("no current account set")
+	var targets []store.Account
+	switch {
+	case logoutAll:
+		if len(args) > 0 {
+			return fmt.Errorf("--all cannot be combined with an account")
+		}
+		for _, acc := range db.Accounts {
+			if !acc.LoggedOut || !logoutKeep {
+				targets = append(targets, acc)
+			}
+		}
+		if len(targets) == 0 {
+			return fmt.Errorf("no accounts to log out of")
+		}
+	case len(args) > 0:
+		targetID, err := findAccount(db, strings.Join(args, " "))
+		if err != nil {
+			return err
+		}
+		targets = append(targets, *db.GetAccount(targetID))
+	default:
+		if db.Current == "" {
+			return fmt.Errorf("no current account set")
+		}
+		acc := db.GetAccount(db.Current)
+		if acc == nil {
+			return fmt.Errorf("current account not found in database")
+		}
+		targets = append(targets, *acc)
 	}
 
-	acc := db.GetAccount(db.Current)
-	if acc == nil {
-		return fmt.Errorf("current account not found in database")
+	var title string
+	switch {
+	case len(targets) > 1 && logoutKeep:
+		title = fmt.Sprintf("Logout from %d accounts?", len(targets))
+	case len(targets) > 1:
+		title = fmt.Sprintf("Logout from %d accounts and remove them from saved accounts?", len(targets))
+	case logoutKeep:
+		title = fmt.Sprintf("Logout from '%s'?", targets[0].Name)
+	default:
+		title = fmt.Sprintf("Logout from '%s' and remove from saved accounts?", targets[0].Name)
 	}
 
-	var confirm bool
-	err = huh.NewConfirm().
-		Title(fmt.Sprintf("Logout from '%s' and remove from saved accounts?", acc.Name)).
-		Value(&confirm).
-		Run()
-
-	if err != nil || !confirm {
+	confirm, err := prompt.Confirm(title)
+	if err != nil {
+		return err
+	}
+	if !confirm {
 		fmt.Println("Cancelled.")
 		return nil
 	}
 
-	// Get wrangler command
-	wranglerCmd, err := wrangler.EnsureWranglerCmd(db)
-	if err != nil {
-		return fmt.Errorf("failed to find wrangler: %w", err)
+	// Log out of the current account last so a failing hook doesn't stop the others
+	for i, acc := range targets {
+		if acc.ID == db.Current && i != len(targets)-1 {
+			targets = append(append(targets[:i:i], targets[i+1:]...), acc)
+			break
+		}
+	}
+
+	failed, stillValid := 0, 0
+	for _, acc := range targets {
+		err := logoutAccount(cmd.Context(), db, acc)
+		if errors.Is(err, errTokenStillValid) {
+			color.Yellow("! %s: logged out, but %v", acc.Name, err)
+			stillValid++
+			continue
+		}
+		if err != nil {
+			color.Red("✗ %s: %v", acc.Name, err)
+			failed++
+			continue
+		}
+		if logoutKeep {
+			color.Green("✓ Logged out: %s", acc.Name)
+		} else {
+			color.Green("✓ Logged out and removed: %s", acc.Name)
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("failed to log out of %d account(s)", failed)
+	}
+	if stillValid > 0 {
+		return fmt.Errorf("%d account(s) have API tokens or keys that are still valid; revoke them in the Cloudflare dashboard", stillValid)
+	}
+
+	if !logoutKeep && !logoutAll && len(db.Accounts) > 0 && db.Current == "" {
+		fmt.Println("\nRemaining accounts:")
+		for _, a := range db.Accounts {
+			fmt.Printf("  • %s (%s)\n", a.Name, a.Email)
+		}
+		fmt.Println("\nUse 'cl switch' to login to another account.")
+	}
+
+	return nil
+}
+
+// errTokenStillValid is returned after logging out of a profile whose
+// credentials cl can't revoke
+var errTokenStillValid = errors.New("its API token or key is still valid; revoke it in the Cloudflare dashboard")
+
+// logoutAccount revokes an account's credentials and removes or marks the
+// profile. A profile with an API token, which only the dashboard can revoke,
+// is only removed once confirmed, and errTokenStillValid is returned.
+func logoutAccount(ctx context.Context, db *store.AccountsDB, acc store.Account) error {
+	wasCurrent := acc.ID == db.Current
+	oldAcc := db.GetAccount(db.Current)
+
+	stillValid := false
+	if !acc.LoggedOut && !canRevoke(db, acc) {
+		if !logoutKeep {
+			confirm, err := prompt.Confirm(fmt.Sprintf("%s uses an API token or key cl can't revoke, so it stays valid. Remove the profile anyway?", acc.Name))
+			if err != nil {
+				return err
+			}
+			if !confirm {
+				return fmt.Errorf("kept: cl can't revoke its API token or key; revoke it in the Cloudflare dashboard or use --keep")
+			}
+		}
+		stillValid = true
 	}
 
-	// Run wrangler logout
-	fmt.Println("Running wrangler logout...")
-	if err := wrangler.Logout(wranglerCmd); err != nil {
-		return fmt.Errorf("wrangler logout failed: %w", err)
+	if wasCurrent {
+		if err := runPreSwitchHooks(db, "logout", nil); err != nil {
+			return err
+		}
+
+		wranglerCmd, err := wrangler.EnsureProfileCmd(db, &acc)
+		if err != nil {
+			return fmt.Errorf("failed to find wrangler: %w", err)
+		}
+
+		fmt.Println("Running wrangler logout...")
+		if err := wrangler.Logout(ctx, wranglerCmd); err != nil {
+			return fmt.Errorf("wrangler logout failed: %w", err)
+		}
+	} else if !acc.LoggedOut && !stillValid {
+		if err := revokeSavedToken(db, acc); err != nil {
+			return err
+		}
 	}
 
 	// Remove from our storage
-	if err := store.DeleteAccountConfig(db.Current); err != nil {
-		// Not fatal - file might already be gone
+	if err := store.DeleteAccountConfig(acc.ID); err != nil && !os.IsNotExist(err) {
+		// Not fatal - the token is already revoked
 		fmt.Printf("Warning: could not delete config file: %v\n", err)
 	}
 
-	accountName := acc.Name
-	db.RemoveAccount(db.Current)
+	if logoutKeep {
+		acc.LoggedOut = true
+		acc.ConfigHash = ""
+		db.AddAccount(acc)
+		if wasCurrent {
+			db.Current = ""
+		}
+	} else {
+		db.RemoveAccount(acc.ID)
+	}
 
 	if err := store.SaveDB(db); err != nil {
 		return fmt.Errorf("failed to save database: %w", err)
 	}
 
-	color.Green("✓ Logged out and removed: %s", accountName)
+	recordEvent(audit.ActionLogout, &acc, oldAcc, db.GetAccount(db.Current))
 
-	if len(db.Accounts) > 0 {
-		fmt.Println("\nRemaining accounts:")
-		for _, a := range db.Accounts {
-			fmt.Printf("  • %s (%s)\n", a.Name, a.Email)
-		}
-		fmt.Println("\nUse 'cl switch' to login to another account.")
+	if wasCurrent {
+		runPostSwitchHooks(db, "logout", &acc)
 	}
 
+	if stillValid {
+		return errTokenStillValid
+	}
+	return nil
+}
+
+// canRevoke reports whether logging out ends the profile's credentials;
+// API tokens and keys can only be revoked in the dashboard
+func canRevoke(db *store.AccountsDB, acc store.Account) bool {
+	info, err := db.GetAccountTokenInfo(acc.ID)
+	if err != nil {
+		// Let the logout itself report the unreadable config
+		return true
+	}
+	return info.Type != store.TokenTypeAPIToken && info.Type != store.TokenTypeAPIKey
+}
+
+// revokeSavedToken revokes the OAuth token stored in a saved profile
+func revokeSavedToken(db *store.AccountsDB, acc store.Account) error {
+	info, err := db.GetAccountTokenInfo(acc.ID)
+	if err != nil {
+		return fmt.Errorf("failed to read saved config: %w", err)
+	}
+
+	if info.RefreshToken == "" {
+		// A bare OAuth access token expires on its own
+		return nil
+	}
+
+	if err := wrangler.RevokeToken(info.RefreshToken); err != nil {
+		return fmt.Errorf("failed to revoke token: %w", err)
+	}
 	return nil
 }
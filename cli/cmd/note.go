@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/groo-dev/cl-wrangler/cli/internal/store"
+	"github.com/spf13/cobra"
+)
+
+var noteCmd = &cobra.Command{
+	Use:   "note <account-name-or-id> [text...]",
+	Short: "Show or set notes for an account",
+	Long: `Shows the notes of a saved account, or replaces them with the given text.
+Use --clear to remove the notes.`,
+	Args:              cobra.MinimumNArgs(1),
+	RunE:              runNote,
+	ValidArgsFunction: completeAccountNames,
+}
+
+var noteClear bool
+
+func init() {
+	noteCmd.Flags().BoolVar(&noteClear, "clear", false, "Remove the notes")
+	rootCmd.AddCommand(noteCmd)
+}
+
+func runNote(cmd *cobra.Command, args []string) error {
+	db, err := store.LoadDB()
+	if err != nil {
+		return fmt.Errorf("failed to load database: %w", err)
+	}
+
+	targetID, err := findAccount(db, args[0])
+	if err != nil {
+		return err
+	}
+	acc := db.GetAccount(targetID)
+
+	text := strings.Join(args[1:], " ")
+	if text == "" && !noteClear {
+		if acc.Notes == "" {
+			fmt.Printf("No notes for %s.\n", acc.Name)
+		} else {
+			fmt.Println(acc.Notes)
+		}
+		return nil
+	}
+	if text != "" && noteClear {
+		return fmt.Errorf("--clear cannot be combined with note text")
+	}
+
+	acc.Notes = text
+	db.AddAccount(*acc)
+	if err := store.SaveDB(db); err != nil {
+		return fmt.Errorf("failed to save database: %w", err)
+	}
+
+	if noteClear {
+		color.Green("✓ Cleared notes for %s", acc.Name)
+	} else {
+		color.Green("✓ Saved notes for %s", acc.Name)
+	}
+
+	return nil
+}
@@ -0,0 +1,160 @@
+package cmd
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/groo-dev/cl-wrangler/cli/internal/output"
+	"github.com/groo-dev/cl-wrangler/cli/internal/store"
+	"github.com/spf13/cobra"
+)
+
+// outputOptions holds the --output and --template flags of a read command
+type outputOptions struct {
+	format   string
+	template string
+}
+
+func addOutputFlags(cmd *cobra.Command) *outputOptions {
+	opts := &outputOptions{}
+	cmd.Flags().StringVarP(&opts.format, "output", "o", string(output.FormatTable), "Output format: table, json, yaml, tsv")
+	cmd.Flags().StringVar(&opts.template, "template", "", "Go template over the JSON fields, e.g. {{.name}} (overrides --output)")
+	return opts
+}
+
+// structured returns true if the command should skip its human-readable output
+func (o *outputOptions) structured() (bool, error) {
+	if o.template != "" {
+		return true, nil
+	}
+	format, err := output.ParseFormat(o.format)
+	if err != nil {
+		return false, err
+	}
+	return format != output.FormatTable, nil
+}
+
+// print writes v using the selected template or format
+func (o *outputOptions) print(v any) error {
+	if o.template != "" {
+		return output.WriteTemplate(os.Stdout, o.template, v)
+	}
+	return output.Write(os.Stdout, output.Format(o.format), v)
+}
+
+// accountView is the stable machine-readable schema for an account
+type accountView struct {
+	ID             string     `json:"id" yaml:"id"`
+	Name           string     `json:"name" yaml:"name"`
+	AccountName    string     `json:"account_name" yaml:"account_name"`
+	Aliases        []string   `json:"aliases" yaml:"aliases"`
+	Notes          string     `json:"notes" yaml:"notes"`
+	Email          string     `json:"email" yaml:"email"`
+	AddedAt        time.Time  `json:"added_at" yaml:"added_at"`
+	LastUsedAt     *time.Time `json:"last_used_at" yaml:"last_used_at"`
+	UseCount       int        `json:"use_count" yaml:"use_count"`
+	Tags           []string   `json:"tags" yaml:"tags"`
+	IsCurrent      bool       `json:"is_current" yaml:"is_current"`
+	LoggedOut      bool       `json:"logged_out" yaml:"logged_out"`
+	ProfileType    string     `json:"profile_type" yaml:"profile_type"`
+	TokenExpiresAt *time.Time `json:"token_expires_at" yaml:"token_expires_at"`
+	TokenExpired   bool       `json:"token_expired" yaml:"token_expired"`
+	WranglerCmd    string     `json:"wrangler_cmd" yaml:"wrangler_cmd"`
+	Color          string     `json:"color" yaml:"color"`
+	Favorite       bool       `json:"favorite" yaml:"favorite"`
+	Protected      bool       `json:"protected" yaml:"protected"`
+	RevertAfter    int        `json:"revert_after" yaml:"revert_after"`
+}
+
+func newAccountView(db *store.AccountsDB, acc store.Account) accountView {
+	view := accountView{
+		ID:          acc.ID,
+		Name:        acc.Name,
+		AccountName: acc.AccountName,
+		Aliases:     append([]string{}, acc.Aliases...),
+		Notes:       acc.Notes,
+		Email:       acc.Email,
+		AddedAt:     acc.AddedAt,
+		UseCount:    acc.UseCount,
+		Tags:        append([]string{}, acc.Tags...),
+		IsCurrent:   acc.ID == db.Current,
+		LoggedOut:   acc.LoggedOut,
+		ProfileType: "unknown",
+		WranglerCmd: acc.WranglerCmd,
+		Color:       acc.Color,
+		Favorite:    acc.Favorite,
+		Protected:   acc.Protected,
+		RevertAfter: acc.RevertAfter,
+	}
+	if !acc.LastUsedAt.IsZero() {
+		lastUsedAt := acc.LastUsedAt
+		view.LastUsedAt = &lastUsedAt
+	}
+
+	info, err := db.GetAccountTokenInfo(acc.ID)
+	if err != nil {
+		return view
+	}
+	if info.Type != "" {
+		view.ProfileType = info.Type
+	}
+	if !info.ExpiresAt.IsZero() {
+		expiresAt := info.ExpiresAt
+		view.TokenExpiresAt = &expiresAt
+		view.TokenExpired = time.Now().After(expiresAt)
+	}
+
+	return view
+}
+
+func (v accountView) row() []string {
+	return []string{
+		v.ID,
+		v.Name,
+		v.AccountName,
+		strings.Join(v.Aliases, ","),
+		v.Notes,
+		v.Email,
+		v.AddedAt.Format(time.RFC3339),
+		formatOptionalTime(v.LastUsedAt),
+		strconv.Itoa(v.UseCount),
+		strings.Join(v.Tags, ","),
+		strconv.FormatBool(v.IsCurrent),
+		strconv.FormatBool(v.LoggedOut),
+		v.ProfileType,
+		formatOptionalTime(v.TokenExpiresAt),
+		strconv.FormatBool(v.TokenExpired),
+		v.WranglerCmd,
+		v.Color,
+		strconv.FormatBool(v.Favorite),
+		strconv.FormatBool(v.Protected),
+		strconv.Itoa(v.RevertAfter),
+	}
+}
+
+func formatOptionalTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+var accountViewHeader = []string{"id", "name", "account_name", "aliases", "notes", "email", "added_at", "last_used_at", "use_count", "tags", "is_current", "logged_out", "profile_type", "token_expires_at", "token_expired", "wrangler_cmd", "color", "favorite", "protected", "revert_after"}
+
+func (v accountView) Header() []string { return accountViewHeader }
+func (v accountView) Rows() [][]string { return [][]string{v.row()} }
+
+// accountListView is a list of accounts that can also be written as TSV
+type accountListView []accountView
+
+func (l accountListView) Header() []string { return accountViewHeader }
+
+func (l accountListView) Rows() [][]string {
+	rows := make([][]string, 0, len(l))
+	for _, v := range l {
+		rows = append(rows, v.row())
+	}
+	return rows
+}
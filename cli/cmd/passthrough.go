@@ -0,0 +1,332 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/groo-dev/cl-wrangler/cli/internal/audit"
+	"github.com/groo-dev/cl-wrangler/cli/internal/project"
+	"github.com/groo-dev/cl-wrangler/cli/internal/store"
+	"github.com/groo-dev/cl-wrangler/cli/internal/wrangler"
+	"github.com/spf13/cobra"
+)
+
+var passthroughCmd = &cobra.Command{
+	Use:   "wrangler <wrangler-args...>",
+	Short: "Run wrangler, showing and guarding the active account",
+	Long: `Runs the real wrangler with the given arguments after printing the account it will use.
+Deploy-like commands are stopped if the project's account_id is a different
+account than the active one, and so are commands whose flags cl can't tell apart
+from the command. A 'wrangler login' to a new account saves it as with 'cl add'.
+A wrangler started by a wrapped one runs the real wrangler without these checks.
+
+Symlink cl as 'wrangler' earlier in PATH to wrap every wrangler call:
+  ln -s "$(command -v cl)" ~/bin/wrangler`,
+	Example: `  cl wrangler deploy --env production
+  cl wrangler whoami`,
+	DisableFlagParsing: true,
+	SilenceUsage:       true,
+	RunE:               runPassthrough,
+}
+
+// deployCommands are the wrangler commands that change a deployed account,
+// with the subcommands that do; nil means the command itself
+var deployCommands = map[string][]string{
+	"deploy":   nil,
+	"publish":  nil,
+	"rollback": nil,
+	"delete":   nil,
+	"versions": {"upload", "deploy"},
+	"triggers": {"deploy"},
+	"secret":   {"put", "delete", "bulk"},
+	"pages":    {"deploy", "publish"},
+}
+
+func init() {
+	rootCmd.AddCommand(passthroughCmd)
+}
+
+// invokedAsWrangler reports whether cl was started through a 'wrangler' symlink
+func invokedAsWrangler() bool {
+	name := strings.TrimSuffix(filepath.Base(os.Args[0]), ".exe")
+	return name == "wrangler"
+}
+
+func runPassthrough(cmd *cobra.Command, args []string) error {
+	if err := wrangler.AvoidSelf(); err != nil {
+		return fmt.Errorf("failed to locate cl: %w", err)
+	}
+
+	// A wrangler started by the wrapped one, e.g. from a build script: the
+	// outer call already showed the account, but a nested deploy may target
+	// another project, so it is still guarded
+	if os.Getenv(wrangler.WrappedEnv) != "" {
+		if command, ambiguous := wranglerCommand(args); ambiguous || isDeployCommand(command) {
+			db, err := store.LoadDB()
+			if err != nil {
+				return fmt.Errorf("failed to load database: %w", err)
+			}
+			if err := guardProjectAccount(db, db.GetAccount(db.Current), args); err != nil {
+				return err
+			}
+		}
+		realCmd, err := wrangler.RealCmd()
+		if err != nil {
+			return fmt.Errorf("failed to find wrangler: %w", err)
+		}
+		return passExitCode(wrangler.Exec(cmd.Context(), realCmd, args...))
+	}
+	os.Setenv(wrangler.WrappedEnv, "1")
+
+	db, err := store.LoadDB()
+	if err != nil {
+		return fmt.Errorf("failed to load database: %w", err)
+	}
+	acc := db.GetAccount(db.Current)
+
+	printEffectiveAccount(acc)
+
+	// When the flags leave the command unclear, guard it like a deploy
+	command, ambiguous := wranglerCommand(args)
+	if ambiguous || isDeployCommand(command) {
+		if err := guardProjectAccount(db, acc, args); err != nil {
+			return err
+		}
+	}
+
+	// A login may be to another account, so the current profile's pin doesn't apply
+	login := len(command) > 0 && command[0] == "login"
+	profile := acc
+	if login {
+		profile = nil
+	}
+	wranglerCmd, err := wrangler.EnsureProfileCmd(db, profile)
+	if err != nil {
+		return fmt.Errorf("failed to find wrangler: %w", err)
+	}
+
+	if login && acc != nil {
+		// Keep the current account's refreshed token before the login replaces it
+		changed, newHash, err := store.SaveAccountConfigIfChanged(acc.ID, acc.ConfigHash)
+		if err == nil && changed {
+			acc.ConfigHash = newHash
+			db.AddAccount(*acc)
+			store.SaveDB(db)
+			recordEvent(audit.ActionRefresh, acc, nil, nil)
+		}
+	}
+
+	runErr := wrangler.Exec(cmd.Context(), wranglerCmd, args...)
+
+	if login {
+		if runErr != nil {
+			// Don't leave a half-written login in place of the current account
+			if acc != nil {
+				store.RestoreAccountConfig(acc.ID)
+			}
+		} else if err := saveWrappedLogin(cmd.Context(), db, wranglerCmd); err != nil {
+			color.Yellow("Warning: could not save the new login: %v", err)
+		}
+	}
+
+	return passExitCode(runErr)
+}
+
+// passExitCode exits with wrangler's exit code if it failed
+func passExitCode(runErr error) error {
+	var exitErr *exec.ExitError
+	if errors.As(runErr, &exitErr) {
+		os.Exit(exitErr.ExitCode())
+	}
+	return runErr
+}
+
+// printEffectiveAccount tells which account wrangler will act on, on stderr
+// so it never mixes with wrangler's output
+func printEffectiveAccount(acc *store.Account) {
+	info := color.New(color.FgCyan)
+	switch {
+	case os.Getenv("CLOUDFLARE_API_TOKEN") != "" || os.Getenv("CLOUDFLARE_API_KEY") != "":
+		info.Fprintln(os.Stderr, "cl: using credentials from the environment, not a saved account")
+	case acc == nil:
+		info.Fprintln(os.Stderr, "cl: no saved account is active")
+	default:
+		info.Fprintf(os.Stderr, "cl: account %s (%s) %s\n", acc.Name, acc.Email, acc.ID)
+	}
+	if id := os.Getenv("CLOUDFLARE_ACCOUNT_ID"); id != "" && (acc == nil || id != acc.ID) {
+		info.Fprintf(os.Stderr, "cl: CLOUDFLARE_ACCOUNT_ID is set to %s\n", id)
+	}
+}
+
+// wranglerCommand returns the command words of wrangler's arguments,
+// skipping flags and their values. An unknown flag followed by a word may
+// take that word as its value; ambiguous reports whether that happened
+// before the words that decide whether the command deploys.
+func wranglerCommand(args []string) (words []string, ambiguous bool) {
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			break
+		}
+		if !strings.HasPrefix(arg, "-") {
+			words = append(words, arg)
+			continue
+		}
+
+		name, _, hasValue := strings.Cut(arg, "=")
+		switch {
+		case hasValue || booleanFlags[name]:
+		case valueFlags[name]:
+			i++
+		case i+1 < len(args) && !strings.HasPrefix(args[i+1], "-") && !deployDecided(words):
+			ambiguous = true
+		}
+	}
+	return words, ambiguous
+}
+
+// valueFlags are the wrangler flags followed by their value
+var valueFlags = map[string]bool{
+	"-c": true, "--config": true,
+	"-e": true, "--env": true,
+	"--cwd":      true,
+	"--env-file": true,
+}
+
+// booleanFlags are the global wrangler flags that take no value
+var booleanFlags = map[string]bool{
+	"-h": true, "--help": true,
+	"-v": true, "--version": true,
+	"-j": true, "--experimental-json-config": true,
+	"--experimental-provision": true, "--x-provision": true,
+	"--experimental-remote-bindings": true, "--x-remote-bindings": true,
+}
+
+// deployDecided reports whether the command words so far settle whether the
+// command deploys, so later flags can't change it
+func deployDecided(words []string) bool {
+	if len(words) == 0 {
+		return false
+	}
+	subcommands, ok := deployCommands[words[0]]
+	return !ok || subcommands == nil || len(words) > 1
+}
+
+// flagValue returns the value of a wrangler flag given as '--name value' or '--name=value'
+func flagValue(args []string, names ...string) string {
+	for i, arg := range args {
+		if arg == "--" {
+			break
+		}
+		for _, name := range names {
+			if arg == name && i+1 < len(args) {
+				return args[i+1]
+			}
+			if value, ok := strings.CutPrefix(arg, name+"="); ok {
+				return value
+			}
+		}
+	}
+	return ""
+}
+
+func isDeployCommand(command []string) bool {
+	if len(command) == 0 {
+		return false
+	}
+	subcommands, ok := deployCommands[command[0]]
+	if !ok {
+		return false
+	}
+	return subcommands == nil || (len(command) > 1 && slices.Contains(subcommands, command[1]))
+}
+
+// guardProjectAccount stops a deploy when the project's account_id names a
+// different account than the one wrangler will use
+func guardProjectAccount(db *store.AccountsDB, acc *store.Account, args []string) error {
+	// wrangler resolves the config from --cwd when it is given
+	dir, err := os.Getwd()
+	if err != nil {
+		return nil
+	}
+	if cwd := flagValue(args, "--cwd"); cwd != "" {
+		dir = resolvePath(dir, cwd)
+	}
+
+	configPath := flagValue(args, "-c", "--config")
+	if configPath == "" {
+		found, ok := project.FindConfig(dir)
+		if !ok {
+			return nil
+		}
+		configPath = found
+	} else {
+		configPath = resolvePath(dir, configPath)
+	}
+
+	projectID, err := project.AccountID(configPath, flagValue(args, "-e", "--env"))
+	if err != nil {
+		return err
+	}
+	if projectID == "" {
+		return nil
+	}
+
+	activeID := os.Getenv("CLOUDFLARE_ACCOUNT_ID")
+	if activeID == "" && acc != nil {
+		activeID = acc.ID
+	}
+	if activeID == "" || activeID == projectID {
+		return nil
+	}
+
+	target := projectID
+	if projectAcc := db.GetAccount(projectID); projectAcc != nil {
+		target = projectAcc.Name
+	}
+	active := activeID
+	if activeAcc := db.GetAccount(activeID); activeAcc != nil {
+		active = activeAcc.Name
+	}
+	return fmt.Errorf("%s deploys to account %s, but wrangler would use %s; run 'cl switch %s' or 'cl exec %s' first",
+		configPath, projectID, active, target, target)
+}
+
+// resolvePath resolves path against dir unless it is absolute
+func resolvePath(dir, path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(dir, path)
+}
+
+// saveWrappedLogin saves the account a wrapped 'wrangler login' logged in to,
+// or refreshes the current account's saved config if it logged in to that again
+func saveWrappedLogin(ctx context.Context, db *store.AccountsDB, wranglerCmd wrangler.Cmd) error {
+	info, err := wrangler.Whoami(ctx, wranglerCmd)
+	if err != nil {
+		return fmt.Errorf("failed to get account info after login: %w", err)
+	}
+
+	if info.AccountID != db.Current {
+		_, err := saveLogin(db, info, false)
+		return err
+	}
+
+	acc := db.GetAccount(db.Current)
+	changed, newHash, err := store.SaveAccountConfigIfChanged(acc.ID, acc.ConfigHash)
+	if err != nil || !changed {
+		return err
+	}
+	acc.ConfigHash = newHash
+	db.AddAccount(*acc)
+	recordEvent(audit.ActionRefresh, acc, nil, nil)
+	return store.SaveDB(db)
+}
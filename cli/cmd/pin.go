@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/groo-dev/cl-wrangler/cli/internal/store"
+	"github.com/groo-dev/cl-wrangler/cli/internal/wrangler"
+	"github.com/spf13/cobra"
+)
+
+var pinCmd = &cobra.Command{
+	Use:   "pin <account-name-or-id> [version | command...]",
+	Short: "Pin the wrangler version used for an account",
+	Long: `Shows or sets the wrangler command used for an account instead of the global one.
+A version such as 3 or 3.78.2 runs 'npx wrangler@<version>'; anything else is a command.
+A single argument is read as a shell-quoted command line; several arguments are
+used as they are, e.g. cl pin dev node "/opt/my tools/wrangler.js".
+The pin is used by 'cl exec', login, logout and whoami for that account.
+A project's own wrangler still wins inside a project. Use --clear to remove the pin.`,
+	Args:              cobra.MinimumNArgs(1),
+	RunE:              runPin,
+	ValidArgsFunction: completeAccountNames,
+}
+
+var pinClear bool
+
+func init() {
+	pinCmd.Flags().BoolVar(&pinClear, "clear", false, "Use the global wrangler command again")
+	rootCmd.AddCommand(pinCmd)
+}
+
+func runPin(cmd *cobra.Command, args []string) error {
+	db, err := store.LoadDB()
+	if err != nil {
+		return fmt.Errorf("failed to load database: %w", err)
+	}
+
+	targetID, err := findAccount(db, args[0])
+	if err != nil {
+		return err
+	}
+	acc := db.GetAccount(targetID)
+
+	// Several arguments were already split by the shell, so quote them back
+	// into a command line rather than losing their quoting
+	var value string
+	if len(args) == 2 {
+		value = strings.TrimSpace(args[1])
+	} else if len(args) > 2 {
+		value = wrangler.Cmd{Argv: args[1:]}.String()
+	}
+	if value == "" && !pinClear {
+		if acc.WranglerCmd == "" {
+			fmt.Printf("%s uses the global wrangler command.\n", acc.Name)
+		} else {
+			fmt.Println(acc.WranglerCmd)
+		}
+		return nil
+	}
+	if value != "" && pinClear {
+		return fmt.Errorf("--clear cannot be combined with a version or command")
+	}
+
+	if pinClear {
+		acc.WranglerCmd = ""
+	} else {
+		acc.WranglerCmd, err = wrangler.PinnedCmdLine(value)
+		if err != nil {
+			return err
+		}
+	}
+
+	db.AddAccount(*acc)
+	if err := store.SaveDB(db); err != nil {
+		return fmt.Errorf("failed to save database: %w", err)
+	}
+
+	if pinClear {
+		color.Green("✓ %s uses the global wrangler command", acc.Name)
+	} else {
+		color.Green("✓ Pinned %s to: %s", acc.Name, acc.WranglerCmd)
+	}
+
+	return nil
+}
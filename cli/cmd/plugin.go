@@ -0,0 +1,159 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/groo-dev/cl-wrangler/cli/internal/config"
+	"github.com/groo-dev/cl-wrangler/cli/internal/plugin"
+	"github.com/groo-dev/cl-wrangler/cli/internal/store"
+	"github.com/groo-dev/cl-wrangler/cli/internal/wrangler"
+	"github.com/spf13/cobra"
+)
+
+var pluginCmd = &cobra.Command{
+	Use:   "plugin",
+	Short: "Manage cl plugins",
+	Long: `Plugins are executables named cl-<name> on your PATH.
+Running 'cl <name>' runs the plugin when cl has no built-in command of that name.`,
+}
+
+var pluginListCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls"},
+	Short:   "List plugins found on PATH",
+	RunE:    runPluginList,
+}
+
+var pluginListOutput *outputOptions
+
+func init() {
+	pluginListOutput = addOutputFlags(pluginListCmd)
+	pluginCmd.AddCommand(pluginListCmd)
+	rootCmd.AddCommand(pluginCmd)
+}
+
+// pluginView is the machine-readable schema for a plugin
+type pluginView struct {
+	Name     string `json:"name" yaml:"name"`
+	Path     string `json:"path" yaml:"path"`
+	Shadowed bool   `json:"shadowed" yaml:"shadowed"`
+}
+
+type pluginListView []pluginView
+
+func (l pluginListView) Header() []string { return []string{"name", "path", "shadowed"} }
+
+func (l pluginListView) Rows() [][]string {
+	rows := make([][]string, 0, len(l))
+	for _, v := range l {
+		rows = append(rows, []string{v.Name, v.Path, fmt.Sprint(v.Shadowed)})
+	}
+	return rows
+}
+
+func runPluginList(cmd *cobra.Command, args []string) error {
+	views := pluginListView{}
+	for _, p := range plugin.List() {
+		views = append(views, pluginView{Name: p.Name, Path: p.Path, Shadowed: isBuiltinCommand(p.Name)})
+	}
+
+	structured, err := pluginListOutput.structured()
+	if err != nil {
+		return err
+	}
+	if structured {
+		return pluginListOutput.print(views)
+	}
+
+	if len(views) == 0 {
+		fmt.Printf("No plugins found. Plugins are executables named %s<name> on your PATH.\n", plugin.Prefix)
+		return nil
+	}
+
+	for _, v := range views {
+		if v.Shadowed {
+			color.Yellow("  %s  %s (shadowed by built-in command)", v.Name, v.Path)
+		} else {
+			fmt.Printf("  %s  %s\n", v.Name, v.Path)
+		}
+	}
+
+	return nil
+}
+
+// isBuiltinCommand returns true if name is a built-in command or alias
+func isBuiltinCommand(name string) bool {
+	if strings.HasPrefix(name, "__") {
+		// Cobra's hidden completion commands
+		return true
+	}
+	rootCmd.InitDefaultHelpCmd()
+	rootCmd.InitDefaultCompletionCmd()
+	for _, c := range rootCmd.Commands() {
+		if c.Name() == name || c.HasAlias(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// runPlugin dispatches 'cl <name> args...' to a cl-<name> executable on PATH
+// when name isn't a built-in command. It returns false if no plugin applies.
+func runPlugin(name string, args []string, yes, disableInput bool) (bool, int) {
+	if strings.HasPrefix(name, "-") || isBuiltinCommand(name) {
+		return false, 0
+	}
+
+	path, ok := plugin.Find(name)
+	if !ok {
+		return false, 0
+	}
+
+	env := pluginEnv()
+	if yes {
+		env = append(env, "CL_YES=1")
+	}
+	if disableInput {
+		env = append(env, "CL_NO_INPUT=1")
+	}
+	code, err := plugin.Run(path, args, env)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: failed to run plugin %s: %v\n", path, err)
+	}
+	return true, code
+}
+
+// pluginEnv describes the cl environment to plugins
+func pluginEnv() []string {
+	configDir, _ := config.GetConfigDir()
+	env := []string{
+		"CL_VERSION=" + Version,
+		"CL_CONFIG_DIR=" + configDir,
+	}
+
+	db, err := store.LoadDB()
+	if err != nil {
+		return env
+	}
+
+	// The wrangler cl itself would run here: the project's, the pinned or the configured one
+	acc := db.GetAccount(db.Current)
+	wranglerCmd := db.Settings.WranglerCmd
+	if cmd, err := wrangler.EnsureProfileCmd(db, acc); err == nil {
+		wranglerCmd = cmd.String()
+	}
+	env = append(env, "CL_WRANGLER_CMD="+wranglerCmd)
+
+	var id, name, email string
+	if acc != nil {
+		id, name, email = acc.ID, acc.Name, acc.Email
+	}
+	return append(env,
+		"CL_CURRENT_ACCOUNT_ID="+id,
+		"CL_CURRENT_ACCOUNT_NAME="+name,
+		"CL_CURRENT_ACCOUNT_EMAIL="+email,
+	)
+}
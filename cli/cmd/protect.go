@@ -0,0 +1,188 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/charmbracelet/huh"
+	"github.com/fatih/color"
+	"github.com/groo-dev/cl-wrangler/cli/internal/prompt"
+	"github.com/groo-dev/cl-wrangler/cli/internal/store"
+	"github.com/spf13/cobra"
+)
+
+var protectCmd = &cobra.Command{
+	Use:   "protect <account-name-or-id>",
+	Short: "Require confirmation before using an account",
+	Long: `Marks a saved account as protected. Switching to it or running 'cl exec' under it
+then requires typing the account name, or --confirm=<name> without a terminal.
+--revert-after switches back to the previous account that many minutes after
+switching to it, unless the previous account is protected too. Use --clear to
+unprotect it.`,
+	Example: `  cl protect acme-prod --revert-after 15
+  cl switch acme-prod --confirm=acme-prod`,
+	Args:              cobra.ExactArgs(1),
+	RunE:              runProtect,
+	ValidArgsFunction: completeAccountNames,
+}
+
+// revertTimerCmd is started in the background to carry out a pending revert
+var revertTimerCmd = &cobra.Command{
+	Use:    "revert-timer",
+	Short:  "Switch back to the previous account when a revert is due",
+	Hidden: true,
+	Args:   cobra.NoArgs,
+	RunE:   runRevertTimer,
+}
+
+var (
+	protectClear       bool
+	protectRevertAfter int
+)
+
+func init() {
+	protectCmd.Flags().BoolVar(&protectClear, "clear", false, "Remove the protection")
+	protectCmd.Flags().IntVar(&protectRevertAfter, "revert-after", 0, "Switch back to the previous account after this many minutes (0 to disable)")
+	rootCmd.AddCommand(protectCmd)
+	rootCmd.AddCommand(revertTimerCmd)
+}
+
+func runProtect(cmd *cobra.Command, args []string) error {
+	db, err := store.LoadDB()
+	if err != nil {
+		return fmt.Errorf("failed to load database: %w", err)
+	}
+
+	targetID, err := findAccount(db, args[0])
+	if err != nil {
+		return err
+	}
+	acc := db.GetAccount(targetID)
+
+	if protectRevertAfter < 0 {
+		return fmt.Errorf("--revert-after must not be negative")
+	}
+	if protectClear && cmd.Flags().Changed("revert-after") {
+		return fmt.Errorf("--clear cannot be combined with --revert-after")
+	}
+
+	acc.Protected = !protectClear
+	if protectClear {
+		acc.RevertAfter = 0
+	} else if cmd.Flags().Changed("revert-after") {
+		acc.RevertAfter = protectRevertAfter
+	}
+
+	db.AddAccount(*acc)
+	if err := store.SaveDB(db); err != nil {
+		return fmt.Errorf("failed to save database: %w", err)
+	}
+
+	switch {
+	case protectClear:
+		color.Green("✓ %s is no longer protected", acc.Name)
+	case acc.RevertAfter > 0:
+		color.Green("✓ Protected %s; switching back after %d minute(s)", acc.Name, acc.RevertAfter)
+	default:
+		color.Green("✓ Protected %s", acc.Name)
+	}
+
+	return nil
+}
+
+// confirmProtected shows a warning for a protected account and makes the
+// user type its name, unless confirm already names it
+func confirmProtected(acc *store.Account, confirm string) error {
+	if !acc.Protected {
+		return nil
+	}
+
+	warning := color.New(color.FgRed, color.Bold)
+	warning.Fprintf(os.Stderr, "⚠  PROTECTED ACCOUNT: %s (%s)\n", acc.Name, acc.Email)
+	fmt.Fprintf(os.Stderr, "   Wrangler commands will run against %s.\n", acc.ID)
+
+	if confirm != "" {
+		if confirm != acc.Name && confirm != acc.ID {
+			return fmt.Errorf("--confirm=%s does not match protected account %s", confirm, acc.Name)
+		}
+		return nil
+	}
+	if err := prompt.Require(fmt.Sprintf("pass --confirm=%q to use protected account %s", acc.Name, acc.Name)); err != nil {
+		return err
+	}
+
+	var typed string
+	return huh.NewInput().
+		Title(fmt.Sprintf("Type %q to continue", acc.Name)).
+		Value(&typed).
+		Validate(func(s string) error {
+			if s != acc.Name {
+				return fmt.Errorf("does not match the account name")
+			}
+			return nil
+		}).
+		WithTheme(huh.ThemeCatppuccin()).
+		Run()
+}
+
+// armRevert schedules a switch back to the previous account after minutes,
+// if acc is still current then
+func armRevert(db *store.AccountsDB, acc *store.Account, minutes int) error {
+	if minutes <= 0 || db.Current != acc.ID {
+		return nil
+	}
+	previous := db.GetAccount(db.Previous)
+	if previous == nil {
+		color.Yellow("Warning: no previous account to switch back to; not scheduling a revert")
+		return nil
+	}
+	if previous.Protected {
+		// Switching to it would need a confirmation nobody is there to give
+		color.Yellow("Warning: the previous account %s is protected; not scheduling a revert", previous.Name)
+		return nil
+	}
+
+	db.Revert = &store.RevertState{
+		From: acc.ID,
+		To:   previous.ID,
+		At:   time.Now().Add(time.Duration(minutes) * time.Minute),
+	}
+	if err := store.SaveDB(db); err != nil {
+		return fmt.Errorf("failed to save database: %w", err)
+	}
+
+	if err := startDetached("revert-timer"); err != nil {
+		return fmt.Errorf("failed to schedule revert: %w", err)
+	}
+
+	fmt.Printf("↺ Switching back to %s at %s\n", previous.Name, db.Revert.At.Local().Format("15:04"))
+	return nil
+}
+
+func runRevertTimer(cmd *cobra.Command, args []string) error {
+	db, err := store.LoadDB()
+	if err != nil || db.Revert == nil {
+		return err
+	}
+	pending := *db.Revert
+	time.Sleep(time.Until(pending.At))
+
+	// Another switch or a newer timer may have taken over while we slept
+	db, err = store.LoadDB()
+	if err != nil {
+		return err
+	}
+	revert := db.Revert
+	if revert == nil || revert.From != pending.From || revert.To != pending.To || !revert.At.Equal(pending.At) || db.Current != pending.From {
+		return nil
+	}
+	// A protected account needs a confirmation the timer can't give
+	previous := db.GetAccount(pending.To)
+	if previous == nil || previous.LoggedOut || previous.Protected {
+		db.Revert = nil
+		return store.SaveDB(db)
+	}
+
+	return switchToAccount(db, previous)
+}
@@ -2,12 +2,14 @@ package cmd
 
 import (
 	"fmt"
+	"os"
 	"strings"
 
 	"github.com/charmbracelet/huh"
 	"github.com/fatih/color"
+	"github.com/groo-dev/cl-wrangler/cli/internal/audit"
+	"github.com/groo-dev/cl-wrangler/cli/internal/prompt"
 	"github.com/groo-dev/cl-wrangler/cli/internal/store"
-	"github.com/sahilm/fuzzy"
 	"github.com/spf13/cobra"
 )
 
@@ -17,12 +19,13 @@ var removeCmd = &cobra.Command{
 	Short:   "Remove a saved account",
 	Long: `Remove a saved Cloudflare/Wrangler account.
 If no argument is provided, shows an interactive list to select from.
-Supports fuzzy matching for account names and IDs.`,
+Accounts are matched the same way as 'cl switch'.`,
 	RunE:              runRemove,
 	ValidArgsFunction: completeAccountNames,
 }
 
 func init() {
+	removeCmd.Flags().BoolVar(&matchExact, "exact", false, "Only match exact account names, aliases and IDs")
 	rootCmd.AddCommand(removeCmd)
 }
 
@@ -39,15 +42,18 @@ func runRemove(cmd *cobra.Command, args []string) error {
 	var targetID string
 
 	if len(args) == 0 {
+		if err := prompt.Require("pass an account name or ID"); err != nil {
+			return err
+		}
+
 		// Interactive selection
 		targetID, err = selectAccountForRemoval(db)
 		if err != nil {
 			return err
 		}
 	} else {
-		// Fuzzy match
 		query := strings.Join(args, " ")
-		targetID, err = findAccountForRemoval(db, query)
+		targetID, err = findAccount(db, query)
 		if err != nil {
 			return err
 		}
@@ -59,12 +65,7 @@ func runRemove(cmd *cobra.Command, args []string) error {
 	}
 
 	// Confirm deletion
-	var confirm bool
-	err = huh.NewConfirm().
-		Title(fmt.Sprintf("Remove account '%s' (%s)?", acc.Name, acc.Email)).
-		Value(&confirm).
-		Run()
-
+	confirm, err := prompt.Confirm(fmt.Sprintf("Remove account '%s' (%s)?", acc.Name, acc.Email))
 	if err != nil {
 		return err
 	}
@@ -74,18 +75,32 @@ func runRemove(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
+	return removeAccount(db, acc)
+}
+
+// removeAccount deletes a saved account and its config
+func removeAccount(db *store.AccountsDB, acc *store.Account) error {
+	oldAcc := db.GetAccount(db.Current)
+	if acc.ID == db.Current {
+		if err := runPreSwitchHooks(db, "remove", nil); err != nil {
+			return err
+		}
+	}
+
 	// Delete config file
-	if err := store.DeleteAccountConfig(targetID); err != nil {
+	if err := store.DeleteAccountConfig(acc.ID); err != nil && !os.IsNotExist(err) {
 		return fmt.Errorf("failed to delete account config: %w", err)
 	}
 
 	// Remove from database
-	db.RemoveAccount(targetID)
+	db.RemoveAccount(acc.ID)
 	if err := store.SaveDB(db); err != nil {
 		return fmt.Errorf("failed to save database: %w", err)
 	}
 
+	recordEvent(audit.ActionRemove, acc, oldAcc, db.GetAccount(db.Current))
 	color.Green("✓ Removed: %s (%s)", acc.Name, acc.Email)
+	runPostSwitchHooks(db, "remove", oldAcc)
 
 	return nil
 }
@@ -111,14 +126,3 @@ func selectAccountForRemoval(db *store.AccountsDB) (string, error) {
 
 	return selected, nil
 }
-
-func findAccountForRemoval(db *store.AccountsDB, query string) (string, error) {
-	source := accountSearchable{accounts: db.Accounts}
-	matches := fuzzy.FindFrom(query, source)
-
-	if len(matches) == 0 {
-		return "", fmt.Errorf("no account found matching: %s", query)
-	}
-
-	return db.Accounts[matches[0].Index].ID, nil
-}
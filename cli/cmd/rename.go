@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/groo-dev/cl-wrangler/cli/internal/store"
+	"github.com/spf13/cobra"
+)
+
+var renameCmd = &cobra.Command{
+	Use:   "rename <account-name-or-id> <new-name>",
+	Short: "Rename a saved account",
+	Long: `Sets the display name of a saved account.
+The name is kept when the account is re-added or logged in again.`,
+	Args:              cobra.ExactArgs(2),
+	RunE:              runRename,
+	ValidArgsFunction: completeAccountNames,
+}
+
+func init() {
+	rootCmd.AddCommand(renameCmd)
+}
+
+func runRename(cmd *cobra.Command, args []string) error {
+	db, err := store.LoadDB()
+	if err != nil {
+		return fmt.Errorf("failed to load database: %w", err)
+	}
+
+	targetID, err := findAccount(db, args[0])
+	if err != nil {
+		return err
+	}
+
+	newName := strings.TrimSpace(args[1])
+	if newName == "" {
+		return fmt.Errorf("name cannot be empty")
+	}
+
+	acc := db.GetAccount(targetID)
+	oldName := acc.Name
+	acc.Name = newName
+	db.AddAccount(*acc)
+
+	if err := store.SaveDB(db); err != nil {
+		return fmt.Errorf("failed to save database: %w", err)
+	}
+
+	color.Green("✓ Renamed: %s → %s", oldName, newName)
+
+	return nil
+}
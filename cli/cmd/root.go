@@ -3,11 +3,14 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/fatih/color"
+	"github.com/groo-dev/cl-wrangler/cli/internal/prompt"
 	"github.com/groo-dev/cl-wrangler/cli/internal/store"
 	"github.com/groo-dev/cl-wrangler/cli/internal/update"
+	"github.com/mattn/go-isatty"
 	"github.com/spf13/cobra"
 )
 
@@ -17,48 +20,158 @@ var rootCmd = &cobra.Command{
 	Long:    `A CLI tool to easily switch between multiple Cloudflare/Wrangler accounts.`,
 	Version: Version,
 	PersistentPreRun: func(cmd *cobra.Command, args []string) {
-		// Skip update check for version and completion commands
-		if cmd.Name() == "version" || cmd.Name() == "completion" {
+		prompt.Configure(assumeYes, noInput)
+
+		if skipUpdateCheck(cmd) {
+			return
+		}
+		startUpdateCheck()
+	},
+	PersistentPostRun: func(cmd *cobra.Command, args []string) {
+		if skipUpdateCheck(cmd) {
 			return
 		}
-		checkForUpdates()
+		finishUpdateCheck()
 	},
 }
 
+var (
+	assumeYes bool
+	noInput   bool
+)
+
+func init() {
+	rootCmd.PersistentFlags().BoolVarP(&assumeYes, "yes", "y", false, "Answer yes to all confirmations")
+	rootCmd.PersistentFlags().BoolVar(&noInput, "no-input", false, "Never prompt; fail if input is required")
+}
+
 func Execute() {
+	// Started through a 'wrangler' symlink: every argument is wrangler's
+	if invokedAsWrangler() {
+		rootCmd.SetArgs(append([]string{passthroughCmd.Name()}, os.Args[1:]...))
+	} else if yes, disableInput, rest := splitRootFlags(os.Args[1:]); len(rest) > 0 {
+		// Dispatch 'cl foo' to a cl-foo plugin when there's no built-in foo
+		prompt.Configure(yes, disableInput)
+		if ran, code := runPlugin(rest[0], rest[1:], yes, disableInput); ran {
+			os.Exit(code)
+		}
+	}
+
 	if err := rootCmd.Execute(); err != nil {
 		os.Exit(1)
 	}
 }
 
-func checkForUpdates() {
+// splitRootFlags takes cl's own --yes and --no-input off the front of args,
+// so 'cl -y foo' still finds the command name
+func splitRootFlags(args []string) (yes, disableInput bool, rest []string) {
+	for i, arg := range args {
+		name, value, hasValue := strings.Cut(arg, "=")
+		on := !hasValue || value == "true" || value == "1"
+		switch {
+		case name == "-y" || name == "--yes":
+			yes = on
+		case name == "--no-input":
+			disableInput = on
+		default:
+			return yes, disableInput, args[i:]
+		}
+	}
+	return yes, disableInput, nil
+}
+
+// skipUpdateCheck returns true for commands that shouldn't check for or announce updates
+func skipUpdateCheck(cmd *cobra.Command) bool {
+	switch cmd.Name() {
+	case "version", "completion", "upgrade", "ci-restore", "ci-cleanup", "revert-timer", "wrangler", "update-check", cobra.ShellCompRequestCmd, cobra.ShellCompNoDescRequestCmd:
+		return true
+	}
+	return false
+}
+
+// startUpdateCheck starts a detached 'cl update-check' at most once per day,
+// so the running command never waits on the network. The result is shown
+// by a later command.
+func startUpdateCheck() {
 	db, err := store.LoadDB()
 	if err != nil {
 		return
 	}
 
-	// Only check once per day
-	if !update.ShouldCheck(db.Settings.LastUpdateCheck) {
+	if !update.Enabled(db.Settings.DisableUpdateCheck) || !update.ShouldAttempt(db.Update.LastCheck, db.Update.LastAttempt) {
 		return
 	}
 
-	// Update last check time
-	db.Settings.LastUpdateCheck = time.Now()
-	store.SaveDB(db)
+	db.Update.LastAttempt = time.Now()
+	if err := store.SaveDB(db); err != nil {
+		return
+	}
+	startDetached(updateCheckCmd.Name())
+}
 
-	// Check for updates in background (don't block CLI)
-	go func() {
-		newVersion, url, err := update.CheckForUpdate(Version)
-		if err != nil || newVersion == "" {
-			return
-		}
+// finishUpdateCheck shows the update notice for a version found by an
+// earlier check, at most once per day
+func finishUpdateCheck() {
+	db, err := store.LoadDB()
+	if err != nil {
+		return
+	}
+	if !update.Enabled(db.Settings.DisableUpdateCheck) {
+		return
+	}
+
+	latest := db.Update.LatestVersion
+	if update.IsNewerVersion(latest, Version) && update.ShouldCheck(db.Update.LastNotified) && isatty.IsTerminal(os.Stderr.Fd()) {
+		// Print update notice to stderr so it never mixes with command output
+		fmt.Fprintln(os.Stderr)
+		color.New(color.FgYellow).Fprintf(os.Stderr, "A new version of cl is available: v%s → v%s\n", Version, latest)
+		fmt.Fprintf(os.Stderr, "Run 'cl upgrade' or download: %s\n\n", update.ReleasePageURL(latest))
+
+		db.Update.LastNotified = time.Now()
+		store.SaveDB(db)
+	}
+}
+
+// updateCheckCmd runs detached from startUpdateCheck and stores the result
+var updateCheckCmd = &cobra.Command{
+	Use:    "update-check",
+	Short:  "Check for a newer version and store the result",
+	Hidden: true,
+	Args:   cobra.NoArgs,
+	RunE:   runUpdateCheck,
+}
+
+func init() {
+	rootCmd.AddCommand(updateCheckCmd)
+}
+
+func runUpdateCheck(cmd *cobra.Command, args []string) error {
+	db, err := store.LoadDB()
+	if err != nil {
+		return err
+	}
+
+	channel := update.NormalizeChannel(db.Settings.UpdateChannel)
+	etag := db.Update.ETag
+	if db.Update.Channel != channel {
+		etag = ""
+	}
 
-		// Print update notice
-		fmt.Println()
-		color.Yellow("A new version of cl is available: v%s → v%s", Version, newVersion)
-		fmt.Printf("Download: %s\n\n", url)
-	}()
+	result, err := update.Fetch(channel, etag)
+	if err != nil {
+		return err
+	}
 
-	// Small delay to allow update message to print
-	time.Sleep(100 * time.Millisecond)
+	// Reload so changes other commands made during the fetch aren't lost
+	db, err = store.LoadDB()
+	if err != nil {
+		return err
+	}
+	db.Update.LastCheck = time.Now()
+	db.Update.Channel = channel
+	db.Update.ETag = result.ETag
+	if !result.NotModified {
+		db.Update.LatestVersion = result.Version
+	}
+	return store.SaveDB(db)
 }
@@ -1,15 +1,17 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"strings"
-	"time"
 
 	"github.com/charmbracelet/huh"
 	"github.com/fatih/color"
+	"github.com/groo-dev/cl-wrangler/cli/internal/audit"
+	"github.com/groo-dev/cl-wrangler/cli/internal/match"
+	"github.com/groo-dev/cl-wrangler/cli/internal/prompt"
 	"github.com/groo-dev/cl-wrangler/cli/internal/store"
 	"github.com/groo-dev/cl-wrangler/cli/internal/wrangler"
-	"github.com/sahilm/fuzzy"
 	"github.com/spf13/cobra"
 )
 
@@ -17,19 +19,39 @@ const (
 	addNewAccountOption = "__add_new__"
 	deleteAccountOption = "__delete__"
 	backOption          = "__back__"
+	groupHeadingOption  = "__group__"
 )
 
 var switchCmd = &cobra.Command{
-	Use:   "switch [account-name-or-id]",
+	Use:   "switch [account-name-or-id | -]",
 	Short: "Switch to a saved account",
 	Long: `Switch to a saved Cloudflare/Wrangler account.
 If no argument is provided, shows an interactive list to select from.
-Supports fuzzy matching for account names and IDs.`,
+Accounts are matched by exact ID, then exact name or alias, then prefix, then fuzzy.
+Use --exact to disable prefix and fuzzy matching.
+Use --tag to only offer or match accounts with a tag.
+Protected accounts need their name typed, or --confirm=<name>.
+Use --revert-after to switch back to the previous account after some minutes.
+Use 'cl switch -' to go back to the previously active account.`,
 	RunE:              runSwitch,
 	ValidArgsFunction: completeAccountNames,
 }
 
+var (
+	// matchExact disables prefix and fuzzy account matching
+	matchExact bool
+	switchTags []string
+
+	switchConfirm     string
+	switchRevertAfter int
+)
+
 func init() {
+	switchCmd.Flags().BoolVar(&matchExact, "exact", false, "Only match exact account names, aliases and IDs")
+	switchCmd.Flags().StringSliceVar(&switchTags, "tag", nil, "Only consider accounts with this tag (repeatable)")
+	switchCmd.RegisterFlagCompletionFunc("tag", completeTags)
+	switchCmd.Flags().StringVar(&switchConfirm, "confirm", "", "Name of the protected account being switched to, instead of typing it")
+	switchCmd.Flags().IntVar(&switchRevertAfter, "revert-after", 0, "Switch back to the previous account after this many minutes (default from 'cl protect')")
 	rootCmd.AddCommand(switchCmd)
 }
 
@@ -48,6 +70,7 @@ func completeAccountNames(cmd *cobra.Command, args []string, toComplete string)
 	for _, acc := range db.Accounts {
 		completions = append(completions, acc.Name)
 		completions = append(completions, acc.ID)
+		completions = append(completions, acc.Aliases...)
 	}
 
 	return completions, cobra.ShellCompDirectiveNoFileComp
@@ -62,29 +85,94 @@ func runSwitch(cmd *cobra.Command, args []string) error {
 	var targetID string
 
 	if len(args) == 0 {
-		// Interactive selection
-		targetID, err = selectAccountInteractive(db)
-		if err != nil {
+		if err := prompt.Require("pass an account name or ID"); err != nil {
 			return err
 		}
 
-		// Handle special options
-		if targetID == addNewAccountOption {
-			return addNewAccount(db)
-		}
-		if targetID == deleteAccountOption {
-			return deleteAccountInteractive(db)
+		// Interactive selection; "Back" from the remove menu returns here
+		for {
+			targetID, err = selectAccountInteractive(db, switchTags)
+			if err != nil {
+				return err
+			}
+
+			// Handle special options
+			switch targetID {
+			case groupHeadingOption:
+				continue
+			case addNewAccountOption:
+				_, err := addNewAccount(cmd.Context(), db, nil)
+				return err
+			case deleteAccountOption:
+				removed, err := deleteAccountInteractive(db)
+				if err != nil || removed {
+					return err
+				}
+				continue
+			}
+			break
 		}
 	} else {
 		if len(db.Accounts) == 0 {
 			return fmt.Errorf("no accounts saved. Use 'cl add' or 'cl switch' to add an account")
 		}
-		// Fuzzy match
 		query := strings.Join(args, " ")
-		targetID, err = findAccountFuzzy(db, query)
+		if query == "-" {
+			// Toggle back to the previous account
+			if db.Previous == "" || db.GetAccount(db.Previous) == nil {
+				return fmt.Errorf("no previous account to switch back to")
+			}
+			targetID = db.Previous
+		} else {
+			accounts := store.FilterByTags(db.AccountsByRecent(), switchTags)
+			if len(accounts) == 0 {
+				return fmt.Errorf("no accounts tagged %s", strings.Join(switchTags, " or "))
+			}
+			targetID, err = findAccountAmong(accounts, query)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	acc := db.GetAccount(targetID)
+	if acc == nil {
+		return fmt.Errorf("account not found")
+	}
+
+	if err := confirmProtected(acc, switchConfirm); err != nil {
+		return err
+	}
+
+	if acc.LoggedOut {
+		confirm, err := prompt.Confirm(fmt.Sprintf("%s is logged out. Log in again?", acc.Name))
 		if err != nil {
+			return fmt.Errorf("%s is logged out: %w", acc.Name, err)
+		}
+		if !confirm {
+			fmt.Println("Cancelled.")
+			return nil
+		}
+		if _, err := addNewAccount(cmd.Context(), db, acc); err != nil {
 			return err
 		}
+	} else if err := switchToAccount(db, acc); err != nil {
+		return err
+	}
+
+	revertAfter := acc.RevertAfter
+	if cmd.Flags().Changed("revert-after") {
+		revertAfter = switchRevertAfter
+	}
+	return armRevert(db, acc, revertAfter)
+}
+
+// switchToAccount saves the current account's config and restores acc's
+func switchToAccount(db *store.AccountsDB, acc *store.Account) error {
+	targetID := acc.ID
+	oldAcc := db.GetAccount(db.Current)
+	if err := runPreSwitchHooks(db, "switch", acc); err != nil {
+		return err
 	}
 
 	// Save current account before switching (if changed)
@@ -95,6 +183,7 @@ func runSwitch(cmd *cobra.Command, args []string) error {
 			if err == nil && changed {
 				currentAcc.ConfigHash = newHash
 				db.AddAccount(*currentAcc)
+				recordEvent(audit.ActionRefresh, currentAcc, nil, nil)
 			}
 		}
 	}
@@ -104,26 +193,41 @@ func runSwitch(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to restore account config: %w", err)
 	}
 
-	db.Current = targetID
+	db.SetCurrent(targetID)
 	if err := store.SaveDB(db); err != nil {
 		return fmt.Errorf("failed to save database: %w", err)
 	}
 
-	acc := db.GetAccount(targetID)
+	recordEvent(audit.ActionSwitch, acc, oldAcc, acc)
 	color.Green("✓ Switched to: %s (%s)", acc.Name, acc.Email)
+	runPostSwitchHooks(db, "switch", oldAcc)
 
 	return nil
 }
 
-func selectAccountInteractive(db *store.AccountsDB) (string, error) {
+func selectAccountInteractive(db *store.AccountsDB, tags []string) (string, error) {
 	var options []huh.Option[string]
 
-	for _, acc := range db.Accounts {
-		label := fmt.Sprintf("  %s (%s)", acc.Name, acc.Email)
-		if acc.ID == db.Current {
-			label = fmt.Sprintf("✓ %s (%s)", acc.Name, acc.Email)
+	// Favorites, then tag groups; most recently used accounts first within each
+	groups := store.GroupAccounts(store.FilterByTags(db.AccountsByRecent(), tags))
+	for _, group := range groups {
+		if len(groups) > 1 || group.Name != "" {
+			options = append(options, huh.NewOption("── "+groupHeading(group)+" ──", groupHeadingOption))
+		}
+		for _, acc := range group.Accounts {
+			mark := "  "
+			if acc.ID == db.Current {
+				mark = "✓ "
+			}
+			label := fmt.Sprintf("%s%s (%s)", mark, accountName(acc), acc.Email)
+			if acc.LoggedOut {
+				label += " [logged out]"
+			}
+			if acc.Protected {
+				label += " [protected]"
+			}
+			options = append(options, huh.NewOption(label, acc.ID))
 		}
-		options = append(options, huh.NewOption(label, acc.ID))
 	}
 
 	// Add action options
@@ -147,11 +251,13 @@ func selectAccountInteractive(db *store.AccountsDB) (string, error) {
 	return selected, nil
 }
 
-func addNewAccount(db *store.AccountsDB) error {
+// addNewAccount runs wrangler login and saves the resulting account as current.
+// target is the profile being logged in again, if any, and picks its pinned wrangler.
+func addNewAccount(ctx context.Context, db *store.AccountsDB, target *store.Account) (*store.Account, error) {
 	// Ensure we have a working wrangler command
-	wranglerCmd, err := wrangler.EnsureWranglerCmd(db)
+	wranglerCmd, err := wrangler.EnsureProfileCmd(db, target)
 	if err != nil {
-		return fmt.Errorf("failed to find wrangler: %w", err)
+		return nil, fmt.Errorf("failed to find wrangler: %w", err)
 	}
 
 	// Save current account first (if there is one and changed)
@@ -164,51 +270,101 @@ func addNewAccount(db *store.AccountsDB) error {
 				currentAcc.ConfigHash = newHash
 				db.AddAccount(*currentAcc)
 				store.SaveDB(db)
+				recordEvent(audit.ActionRefresh, currentAcc, nil, nil)
 			}
 		}
 	}
 
 	// Run wrangler login
 	fmt.Println("Opening browser for Cloudflare login...")
-	if err := wrangler.Login(wranglerCmd); err != nil {
-		return fmt.Errorf("wrangler login failed: %w", err)
+	if err := wrangler.Login(ctx, wranglerCmd); err != nil {
+		// Don't leave a half-written login in place of the current account
+		if db.Current != "" {
+			store.RestoreAccountConfig(db.Current)
+		}
+		return nil, fmt.Errorf("wrangler login failed: %w", err)
 	}
 
 	// Get new account info
 	fmt.Println("Getting new account info...")
-	info, err := wrangler.Whoami(wranglerCmd)
+	info, err := wrangler.Whoami(ctx, wranglerCmd)
 	if err != nil {
-		return fmt.Errorf("failed to get account info after login: %w", err)
+		// The login can't be saved without knowing whose it is
+		if db.Current != "" {
+			store.RestoreAccountConfig(db.Current)
+		}
+		return nil, fmt.Errorf("failed to get account info after login: %w", err)
+	}
+
+	// Logging a profile back in must not save another identity in its place
+	if target != nil && info.AccountID != target.ID {
+		if db.Current != "" {
+			store.RestoreAccountConfig(db.Current)
+		}
+		return nil, fmt.Errorf("logged in to %s (%s), not %s (%s); nothing was saved. Use 'cl add' to save a new account",
+			info.AccountName, info.AccountID, target.Name, target.ID)
+	}
+
+	// Logging a profile back in is confirmed by whoever picked it
+	return saveLogin(db, info, target != nil)
+}
+
+// saveLogin saves the account wrangler just logged in to and makes it current.
+// Unless confirmed, a protected account must be confirmed first and its
+// revert is armed afterwards, as with 'cl switch'.
+func saveLogin(db *store.AccountsDB, info *wrangler.WhoamiInfo, confirmed bool) (*store.Account, error) {
+	account := db.AccountForLogin(info.AccountID, info.AccountName, info.Email)
+
+	oldAcc := db.GetAccount(db.Current)
+	confirm := !confirmed && account.ID != db.Current
+	if confirm {
+		if err := confirmProtected(&account, ""); err != nil {
+			// Put the previous account's credentials back
+			if oldAcc != nil {
+				store.RestoreAccountConfig(oldAcc.ID)
+			}
+			return nil, err
+		}
+	}
+	if err := runPreSwitchHooks(db, "add", &account); err != nil {
+		// Put the previous account's credentials back
+		if oldAcc != nil {
+			store.RestoreAccountConfig(oldAcc.ID)
+		}
+		return nil, err
 	}
 
 	// Save the new account
 	configHash, err := store.SaveAccountConfig(info.AccountID)
 	if err != nil {
-		return fmt.Errorf("failed to save account config: %w", err)
+		return nil, fmt.Errorf("failed to save account config: %w", err)
 	}
 
-	account := store.Account{
-		ID:         info.AccountID,
-		Name:       info.AccountName,
-		Email:      info.Email,
-		AddedAt:    time.Now(),
-		ConfigHash: configHash,
-	}
+	account.ConfigHash = configHash
 	db.AddAccount(account)
-	db.Current = info.AccountID
+	db.SetCurrent(info.AccountID)
 
 	if err := store.SaveDB(db); err != nil {
-		return fmt.Errorf("failed to save database: %w", err)
+		return nil, fmt.Errorf("failed to save database: %w", err)
 	}
 
+	recordEvent(audit.ActionAdd, &account, oldAcc, &account)
 	color.Green("✓ Logged in and saved: %s (%s)", info.AccountName, info.Email)
+	runPostSwitchHooks(db, "add", oldAcc)
 
-	return nil
+	if confirm {
+		if err := armRevert(db, &account, account.RevertAfter); err != nil {
+			return nil, err
+		}
+	}
+	return &account, nil
 }
 
-func deleteAccountInteractive(db *store.AccountsDB) error {
+// deleteAccountInteractive lets the user pick an account to remove.
+// It reports false when the user went back to the main menu.
+func deleteAccountInteractive(db *store.AccountsDB) (bool, error) {
 	if len(db.Accounts) == 0 {
-		return fmt.Errorf("no accounts to remove")
+		return false, fmt.Errorf("no accounts to remove")
 	}
 
 	// Select account to delete
@@ -231,17 +387,17 @@ func deleteAccountInteractive(db *store.AccountsDB) error {
 		Run()
 
 	if err != nil {
-		return err
+		return false, err
 	}
 
 	// Handle back option - return to main menu
 	if selectedID == backOption {
-		return runSwitch(nil, nil)
+		return false, nil
 	}
 
 	acc := db.GetAccount(selectedID)
 	if acc == nil {
-		return fmt.Errorf("account not found")
+		return false, fmt.Errorf("account not found")
 	}
 
 	// Confirm deletion
@@ -255,55 +411,64 @@ func deleteAccountInteractive(db *store.AccountsDB) error {
 		Run()
 
 	if err != nil {
-		return err
+		return false, err
 	}
 
 	if !confirm {
 		fmt.Println("Cancelled")
-		return nil
+		return true, nil
 	}
 
-	// Remove the account
-	if err := store.DeleteAccountConfig(selectedID); err != nil {
-		return fmt.Errorf("failed to remove account config: %w", err)
-	}
+	return true, removeAccount(db, acc)
+}
 
-	db.RemoveAccount(selectedID)
-	if db.Current == selectedID {
-		db.Current = ""
-	}
+// findAccount resolves an account name, alias or ID using the shared matcher.
+// Exact IDs win over exact names and aliases, then prefixes, then fuzzy matches.
+// When the best candidates are too close, the user picks one, or in
+// non-interactive mode an error lists them.
+func findAccount(db *store.AccountsDB, query string) (string, error) {
+	return findAccountAmong(db.AccountsByRecent(), query)
+}
 
-	if err := store.SaveDB(db); err != nil {
-		return fmt.Errorf("failed to save database: %w", err)
+// findAccountAmong is findAccount limited to the given accounts
+func findAccountAmong(accounts []store.Account, query string) (string, error) {
+	candidates := match.Find(accounts, query, matchExact)
+	if len(candidates) == 0 {
+		if matchExact {
+			return "", fmt.Errorf("no account with name, alias or ID: %s", query)
+		}
+		return "", fmt.Errorf("no account found matching: %s", query)
 	}
 
-	color.Green("✓ Removed: %s (%s)", acc.Name, acc.Email)
-
-	return nil
-}
-
-// accountSearchable implements fuzzy.Source for accounts
-type accountSearchable struct {
-	accounts []store.Account
-}
+	best := match.Best(candidates)
+	if len(best) == 1 {
+		return best[0].Account.ID, nil
+	}
 
-func (a accountSearchable) String(i int) string {
-	acc := a.accounts[i]
-	return fmt.Sprintf("%s %s %s", acc.Name, acc.Email, acc.ID)
-}
+	if !prompt.Interactive() {
+		var labels []string
+		for _, c := range best {
+			labels = append(labels, "  "+c.Label())
+		}
+		return "", fmt.Errorf("'%s' matches several accounts; use an ID or alias:\n%s", query, strings.Join(labels, "\n"))
+	}
 
-func (a accountSearchable) Len() int {
-	return len(a.accounts)
-}
+	var options []huh.Option[string]
+	for _, c := range best {
+		options = append(options, huh.NewOption(c.Label(), c.Account.ID))
+	}
 
-func findAccountFuzzy(db *store.AccountsDB, query string) (string, error) {
-	source := accountSearchable{accounts: db.Accounts}
-	matches := fuzzy.FindFrom(query, source)
+	var selected string
+	err := huh.NewSelect[string]().
+		Title(fmt.Sprintf("'%s' matches several accounts", query)).
+		Options(options...).
+		Value(&selected).
+		WithTheme(huh.ThemeCatppuccin()).
+		Run()
 
-	if len(matches) == 0 {
-		return "", fmt.Errorf("no account found matching: %s", query)
+	if err != nil {
+		return "", err
 	}
 
-	// Return the best match
-	return db.Accounts[matches[0].Index].ID, nil
+	return selected, nil
 }
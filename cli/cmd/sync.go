@@ -0,0 +1,164 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/groo-dev/cl-wrangler/cli/internal/manifest"
+	"github.com/groo-dev/cl-wrangler/cli/internal/prompt"
+	"github.com/groo-dev/cl-wrangler/cli/internal/store"
+	"github.com/spf13/cobra"
+)
+
+var syncCmd = &cobra.Command{
+	Use:   "sync <manifest>",
+	Short: "Reconcile saved accounts with a team manifest",
+	Long: `Compares saved accounts with a YAML or JSON team manifest:
+
+  accounts:
+    - id: 0123456789abcdef0123456789abcdef
+      name: Acme Production
+      email: ops@acme.com
+      tags: [prod, client:acme]
+
+Saved accounts are renamed to their canonical names and given the manifest's tags.
+Accounts missing locally are offered for login, and saved accounts that aren't
+in the manifest are reported.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runSync,
+}
+
+var (
+	syncDryRun    bool
+	syncSkipLogin bool
+)
+
+func init() {
+	syncCmd.Flags().BoolVar(&syncDryRun, "dry-run", false, "Show what would change without changing anything")
+	syncCmd.Flags().BoolVar(&syncSkipLogin, "skip-login", false, "Don't offer to log in to missing accounts")
+	rootCmd.AddCommand(syncCmd)
+}
+
+func runSync(cmd *cobra.Command, args []string) error {
+	m, err := manifest.Load(args[0])
+	if err != nil {
+		return err
+	}
+
+	db, err := store.LoadDB()
+	if err != nil {
+		return fmt.Errorf("failed to load database: %w", err)
+	}
+
+	plan := manifest.Reconcile(m, db)
+
+	// Canonical names and tags
+	for _, r := range plan.Renames {
+		fmt.Printf("Rename: %s → %s\n", r.OldName, r.NewName)
+	}
+	for _, id := range plan.Retags {
+		local := db.GetAccount(id)
+		missing := manifest.MissingTags(local.Tags, m.Get(id).Tags)
+		fmt.Printf("Tag: %s + %s\n", local.Name, strings.Join(missing, ", "))
+	}
+	updated := make(map[string]bool)
+	for _, r := range plan.Renames {
+		updated[r.ID] = true
+	}
+	for _, id := range plan.Retags {
+		updated[id] = true
+	}
+	if !syncDryRun && len(updated) > 0 {
+		for id := range updated {
+			acc := db.GetAccount(id)
+			m.Get(id).Apply(acc)
+			db.AddAccount(*acc)
+		}
+		if err := store.SaveDB(db); err != nil {
+			return fmt.Errorf("failed to save database: %w", err)
+		}
+	}
+
+	for _, mm := range plan.EmailMismatches {
+		color.Yellow("Email mismatch: %s is logged in as %s, expected %s", mm.Name, mm.Actual, mm.Expected)
+	}
+
+	for _, acc := range plan.Unknown {
+		color.Yellow("Not in manifest: %s (%s) %s", acc.Name, acc.Email, acc.ID)
+	}
+
+	for _, acc := range plan.Missing {
+		color.Red("Missing: %s %s", acc.Name, acc.ID)
+	}
+
+	if !syncDryRun && !syncSkipLogin && len(plan.Missing) > 0 {
+		if err := loginMissingAccounts(cmd.Context(), db, m, plan.Missing); err != nil {
+			return err
+		}
+	}
+
+	if len(updated) == 0 && len(plan.Missing) == 0 && len(plan.Unknown) == 0 && len(plan.EmailMismatches) == 0 {
+		color.Green("✓ Saved accounts match the manifest")
+	} else if syncDryRun && len(updated) > 0 {
+		color.Cyan("Would update %d account(s); run without --dry-run to apply", len(updated))
+	} else if !syncDryRun && len(updated) > 0 {
+		color.Green("✓ Updated %d account(s)", len(updated))
+	}
+
+	return nil
+}
+
+// loginMissingAccounts walks through wrangler logins for accounts that aren't saved yet
+func loginMissingAccounts(ctx context.Context, db *store.AccountsDB, m *manifest.Manifest, missing []manifest.Account) error {
+	if !prompt.Interactive() && !prompt.AssumeYes() {
+		fmt.Println("\nRun 'cl sync' in a terminal (or with --yes) to log in to missing accounts.")
+		return nil
+	}
+
+	for _, want := range missing {
+		if db.GetAccount(want.ID) != nil {
+			// Picked up by an earlier login
+			continue
+		}
+
+		fmt.Println()
+		title := fmt.Sprintf("Log in to %s (%s) now?", want.Name, want.ID)
+		if want.Email != "" {
+			title = fmt.Sprintf("Log in to %s (%s) as %s now?", want.Name, want.ID, want.Email)
+		}
+		confirm, err := prompt.Confirm(title)
+		if err != nil {
+			return err
+		}
+		if !confirm {
+			continue
+		}
+
+		acc, err := addNewAccount(ctx, db, nil)
+		if err != nil {
+			return err
+		}
+
+		canonical := m.Get(acc.ID)
+		if canonical == nil {
+			color.Yellow("Logged in to %s (%s), which is not in the manifest", acc.Name, acc.ID)
+			continue
+		}
+		if acc.ID != want.ID {
+			color.Yellow("Logged in to %s instead of %s", canonical.Name, want.Name)
+		}
+		if canonical.Email != "" && !strings.EqualFold(acc.Email, canonical.Email) {
+			color.Yellow("Email mismatch: logged in as %s, expected %s", acc.Email, canonical.Email)
+		}
+
+		canonical.Apply(acc)
+		db.AddAccount(*acc)
+		if err := store.SaveDB(db); err != nil {
+			return fmt.Errorf("failed to save database: %w", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/groo-dev/cl-wrangler/cli/internal/store"
+	"github.com/spf13/cobra"
+)
+
+var tagCmd = &cobra.Command{
+	Use:   "tag",
+	Short: "Manage account tags",
+	Long: `Tags group saved accounts, e.g. 'prod' or 'client:acme'.
+'cl list' and the switch menu group accounts by their first tag, and
+--tag limits 'cl list', 'cl switch' and 'cl check' to tagged accounts.`,
+}
+
+var tagAddCmd = &cobra.Command{
+	Use:               "add <account-name-or-id> <tag>...",
+	Short:             "Add tags to an account",
+	Args:              cobra.MinimumNArgs(2),
+	RunE:              runTagAdd,
+	ValidArgsFunction: completeAccountNames,
+}
+
+var tagRemoveCmd = &cobra.Command{
+	Use:               "remove <account-name-or-id> <tag>...",
+	Aliases:           []string{"rm"},
+	Short:             "Remove tags from an account",
+	Args:              cobra.MinimumNArgs(2),
+	RunE:              runTagRemove,
+	ValidArgsFunction: completeAccountNames,
+}
+
+func init() {
+	tagCmd.AddCommand(tagAddCmd)
+	tagCmd.AddCommand(tagRemoveCmd)
+	rootCmd.AddCommand(tagCmd)
+}
+
+func runTagAdd(cmd *cobra.Command, args []string) error {
+	db, err := store.LoadDB()
+	if err != nil {
+		return fmt.Errorf("failed to load database: %w", err)
+	}
+
+	targetID, err := findAccount(db, args[0])
+	if err != nil {
+		return err
+	}
+	acc := db.GetAccount(targetID)
+
+	for _, tag := range args[1:] {
+		if tag == "" || strings.ContainsAny(tag, " \t\n,") {
+			return fmt.Errorf("invalid tag '%s'", tag)
+		}
+		if !acc.HasTag(tag) {
+			acc.Tags = append(acc.Tags, tag)
+		}
+	}
+
+	db.AddAccount(*acc)
+	if err := store.SaveDB(db); err != nil {
+		return fmt.Errorf("failed to save database: %w", err)
+	}
+
+	color.Green("✓ Tags for %s: %s", acc.Name, strings.Join(acc.Tags, ", "))
+
+	return nil
+}
+
+func runTagRemove(cmd *cobra.Command, args []string) error {
+	db, err := store.LoadDB()
+	if err != nil {
+		return fmt.Errorf("failed to load database: %w", err)
+	}
+
+	targetID, err := findAccount(db, args[0])
+	if err != nil {
+		return err
+	}
+	acc := db.GetAccount(targetID)
+
+	for _, tag := range args[1:] {
+		if !acc.HasTag(tag) {
+			return fmt.Errorf("%s has no tag '%s'", acc.Name, tag)
+		}
+		var kept []string
+		for _, existing := range acc.Tags {
+			if existing != tag {
+				kept = append(kept, existing)
+			}
+		}
+		acc.Tags = kept
+	}
+
+	db.AddAccount(*acc)
+	if err := store.SaveDB(db); err != nil {
+		return fmt.Errorf("failed to save database: %w", err)
+	}
+
+	color.Green("✓ Removed tag(s) from %s", acc.Name)
+
+	return nil
+}
+
+// completeTags provides shell completion for the tags in use
+func completeTags(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	db, err := store.LoadDB()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	seen := map[string]bool{}
+	var completions []string
+	for _, acc := range db.Accounts {
+		for _, tag := range acc.Tags {
+			if !seen[tag] {
+				seen[tag] = true
+				completions = append(completions, tag)
+			}
+		}
+	}
+
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
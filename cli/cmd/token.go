@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/groo-dev/cl-wrangler/cli/internal/audit"
+	"github.com/groo-dev/cl-wrangler/cli/internal/store"
+	"github.com/groo-dev/cl-wrangler/cli/internal/wrangler"
+)
+
+// tokenRefreshMargin also refreshes tokens that are about to expire
+const tokenRefreshMargin = time.Minute
+
+// freshTokenInfo returns an account's credentials, first refreshing an
+// expired OAuth token and saving it to the account's config
+func freshTokenInfo(db *store.AccountsDB, acc *store.Account) (*store.TokenInfo, error) {
+	path, err := db.AccountConfigPath(acc.ID)
+	if err != nil {
+		return nil, err
+	}
+	info, err := store.ReadTokenInfo(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config of %s: %w", acc.Name, err)
+	}
+
+	if info.Type != store.TokenTypeOAuth || info.ExpiresAt.IsZero() || time.Until(info.ExpiresAt) > tokenRefreshMargin {
+		return info, nil
+	}
+	if info.RefreshToken == "" {
+		return nil, fmt.Errorf("the token of %s has expired and can't be refreshed; log in again", acc.Name)
+	}
+
+	token, err := wrangler.RefreshOAuthToken(info.RefreshToken)
+	if err != nil {
+		return nil, fmt.Errorf("the token of %s has expired: %w", acc.Name, err)
+	}
+	if err := store.UpdateOAuthToken(path, token.AccessToken, token.RefreshToken, token.ExpiresAt); err != nil {
+		return nil, fmt.Errorf("failed to save refreshed token: %w", err)
+	}
+
+	// Keep the saved copy of the current account in step with the live config,
+	// and the recorded hash in step with the saved copy
+	var hash string
+	if acc.ID == db.Current {
+		hash, err = store.SaveAccountConfig(acc.ID)
+	} else {
+		hash, err = store.HashFile(path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to save account config: %w", err)
+	}
+	acc.ConfigHash = hash
+	db.AddAccount(*acc)
+	if err := store.SaveDB(db); err != nil {
+		return nil, fmt.Errorf("failed to save database: %w", err)
+	}
+	recordEvent(audit.ActionRefresh, acc, nil, nil)
+
+	return store.ReadTokenInfo(path)
+}
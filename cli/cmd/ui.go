@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/groo-dev/cl-wrangler/cli/internal/audit"
+	"github.com/groo-dev/cl-wrangler/cli/internal/prompt"
+	"github.com/groo-dev/cl-wrangler/cli/internal/store"
+	"github.com/groo-dev/cl-wrangler/cli/internal/tui"
+	"github.com/spf13/cobra"
+)
+
+var uiCmd = &cobra.Command{
+	Use:     "ui",
+	Aliases: []string{"dashboard"},
+	Short:   "Manage accounts in a full-screen dashboard",
+	Long: `Opens a full-screen dashboard with a searchable account list and details.
+Keys: enter switch, r refresh, n rename, l re-login, x remove, a add, / search, q quit.`,
+	Args: cobra.NoArgs,
+	RunE: runUI,
+}
+
+func init() {
+	rootCmd.AddCommand(uiCmd)
+}
+
+func runUI(cmd *cobra.Command, args []string) error {
+	if err := prompt.Require("use 'cl list', 'cl switch <account>' and friends instead"); err != nil {
+		return err
+	}
+
+	return tui.Run(tui.Actions{
+		Load: store.LoadDB,
+		Switch: func(id string) error {
+			db, acc, err := loadAccount(id)
+			if err != nil {
+				return err
+			}
+			if err := confirmProtected(acc, ""); err != nil {
+				return err
+			}
+			if err := switchToAccount(db, acc); err != nil {
+				return err
+			}
+			return armRevert(db, acc, acc.RevertAfter)
+		},
+		Login: func(id string) (*store.Account, error) {
+			db, err := store.LoadDB()
+			if err != nil {
+				return nil, fmt.Errorf("failed to load database: %w", err)
+			}
+			target := db.GetAccount(id)
+			if target != nil {
+				if err := confirmProtected(target, ""); err != nil {
+					return nil, err
+				}
+			}
+			acc, err := addNewAccount(cmd.Context(), db, target)
+			if err != nil || target == nil {
+				return acc, err
+			}
+			return acc, armRevert(db, acc, acc.RevertAfter)
+		},
+		Remove: func(id string) error {
+			db, acc, err := loadAccount(id)
+			if err != nil {
+				return err
+			}
+			return removeAccount(db, acc)
+		},
+		Rename: func(id, name string) error {
+			db, acc, err := loadAccount(id)
+			if err != nil {
+				return err
+			}
+			acc.Name = strings.TrimSpace(name)
+			db.AddAccount(*acc)
+			return store.SaveDB(db)
+		},
+		Refresh: refreshCurrentAccount,
+	})
+}
+
+// loadAccount reloads the database and looks up an account by ID
+func loadAccount(id string) (*store.AccountsDB, *store.Account, error) {
+	db, err := store.LoadDB()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load database: %w", err)
+	}
+	acc := db.GetAccount(id)
+	if acc == nil {
+		return nil, nil, fmt.Errorf("account not found")
+	}
+	return db, acc, nil
+}
+
+// refreshCurrentAccount saves the live wrangler config of the current account
+// if wrangler has refreshed its token since the last save
+func refreshCurrentAccount() error {
+	db, err := store.LoadDB()
+	if err != nil {
+		return fmt.Errorf("failed to load database: %w", err)
+	}
+	acc := db.GetAccount(db.Current)
+	if acc == nil {
+		return nil
+	}
+
+	changed, newHash, err := store.SaveAccountConfigIfChanged(acc.ID, acc.ConfigHash)
+	if err != nil {
+		return fmt.Errorf("failed to save account config: %w", err)
+	}
+	if !changed {
+		return nil
+	}
+
+	acc.ConfigHash = newHash
+	db.AddAccount(*acc)
+	if err := store.SaveDB(db); err != nil {
+		return fmt.Errorf("failed to save database: %w", err)
+	}
+	recordEvent(audit.ActionRefresh, acc, nil, nil)
+	return nil
+}
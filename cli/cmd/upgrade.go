@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/groo-dev/cl-wrangler/cli/internal/prompt"
+	"github.com/groo-dev/cl-wrangler/cli/internal/store"
+	"github.com/groo-dev/cl-wrangler/cli/internal/update"
+	"github.com/spf13/cobra"
+)
+
+var upgradeCmd = &cobra.Command{
+	Use:   "upgrade",
+	Short: "Upgrade cl to the latest version",
+	Long: `Downloads the release archive for this OS and architecture, verifies it
+against the release's checksums.txt and replaces the running binary.
+Installs managed by Homebrew, npm or pip are upgraded with that package manager instead.
+The release URL can be overridden with --base-url or CL_RELEASE_URL.`,
+	RunE: runUpgrade,
+}
+
+var (
+	upgradeVersion string
+	upgradeBaseURL string
+	upgradeForce   bool
+)
+
+func init() {
+	upgradeCmd.Flags().StringVar(&upgradeVersion, "version", "", "Version to install (default: latest)")
+	upgradeCmd.Flags().StringVar(&upgradeBaseURL, "base-url", "", "Release base URL (default: GitHub releases)")
+	upgradeCmd.Flags().BoolVar(&upgradeForce, "force", false, "Replace the binary even if up to date or managed by a package manager")
+	rootCmd.AddCommand(upgradeCmd)
+}
+
+func runUpgrade(cmd *cobra.Command, args []string) error {
+	exePath, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to locate cl binary: %w", err)
+	}
+	if resolved, err := filepath.EvalSymlinks(exePath); err == nil {
+		exePath = resolved
+	}
+
+	method := update.DetectInstallMethod(exePath)
+	if method != update.InstallBinary && !upgradeForce {
+		return upgradeWithPackageManager(method)
+	}
+
+	targetVersion := strings.TrimPrefix(upgradeVersion, "v")
+	if targetVersion == "" {
+		fmt.Println("Checking for the latest version...")
+		db, err := store.LoadDB()
+		if err != nil {
+			return fmt.Errorf("failed to load database: %w", err)
+		}
+		targetVersion, err = update.LatestVersion(update.NormalizeChannel(db.Settings.UpdateChannel))
+		if err != nil {
+			return fmt.Errorf("failed to check latest version: %w", err)
+		}
+	}
+
+	if !upgradeForce && !update.IsNewerVersion(targetVersion, Version) {
+		fmt.Printf("cl v%s is up to date (latest: v%s). Use --force to reinstall.\n", Version, targetVersion)
+		return nil
+	}
+
+	baseURL := upgradeBaseURL
+	if baseURL == "" {
+		baseURL = update.ReleaseURL()
+	}
+
+	fmt.Printf("Downloading cl v%s...\n", targetVersion)
+	binary, signed, err := update.DownloadRelease(strings.TrimSuffix(baseURL, "/"), targetVersion)
+	if err != nil {
+		return err
+	}
+
+	if err := update.ReplaceExecutable(exePath, binary); err != nil {
+		return fmt.Errorf("failed to replace %s: %w", exePath, err)
+	}
+
+	if signed {
+		fmt.Println("Verified checksum and signature")
+	} else {
+		fmt.Println("Verified checksum")
+	}
+	color.Green("✓ Upgraded cl v%s → v%s", Version, targetVersion)
+
+	return nil
+}
+
+func upgradeWithPackageManager(method update.InstallMethod) error {
+	command := method.UpgradeCommand()
+	commandLine := strings.Join(command, " ")
+
+	fmt.Printf("cl was installed with %s.\n", method)
+	confirm, err := prompt.Confirm(fmt.Sprintf("Run '%s'?", commandLine))
+	if err != nil {
+		return fmt.Errorf("%w (or run '%s' yourself)", err, commandLine)
+	}
+	if !confirm {
+		fmt.Println("Cancelled.")
+		return nil
+	}
+
+	c := exec.Command(command[0], command[1:]...)
+	c.Stdin = os.Stdin
+	c.Stdout = os.Stdout
+	c.Stderr = os.Stderr
+	if err := c.Run(); err != nil {
+		return fmt.Errorf("%s failed: %w", commandLine, err)
+	}
+
+	return nil
+}
@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"fmt"
+	"runtime"
 
 	"github.com/spf13/cobra"
 )
@@ -13,11 +14,41 @@ var Version = "0.1.0"
 var versionCmd = &cobra.Command{
 	Use:   "version",
 	Short: "Show version information",
-	Run: func(cmd *cobra.Command, args []string) {
-		fmt.Printf("cl v%s\n", Version)
-	},
+	RunE:  runVersion,
 }
 
+var versionOutput *outputOptions
+
 func init() {
+	versionOutput = addOutputFlags(versionCmd)
 	rootCmd.AddCommand(versionCmd)
 }
+
+// versionView is the machine-readable schema for version information
+type versionView struct {
+	Version   string `json:"version" yaml:"version"`
+	GoVersion string `json:"go_version" yaml:"go_version"`
+	OS        string `json:"os" yaml:"os"`
+	Arch      string `json:"arch" yaml:"arch"`
+}
+
+func (v versionView) Header() []string { return []string{"version", "go_version", "os", "arch"} }
+func (v versionView) Rows() [][]string { return [][]string{{v.Version, v.GoVersion, v.OS, v.Arch}} }
+
+func runVersion(cmd *cobra.Command, args []string) error {
+	structured, err := versionOutput.structured()
+	if err != nil {
+		return err
+	}
+	if structured {
+		return versionOutput.print(versionView{
+			Version:   Version,
+			GoVersion: runtime.Version(),
+			OS:        runtime.GOOS,
+			Arch:      runtime.GOARCH,
+		})
+	}
+
+	fmt.Printf("cl v%s\n", Version)
+	return nil
+}
@@ -0,0 +1,235 @@
+package audit
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/user"
+	"strings"
+	"time"
+
+	"github.com/groo-dev/cl-wrangler/cli/internal/config"
+)
+
+// Actions recorded in the audit log
+const (
+	ActionAdd     = "add"
+	ActionSwitch  = "switch"
+	ActionRemove  = "remove"
+	ActionLogout  = "logout"
+	ActionRefresh = "refresh"
+)
+
+// Event is one line of the audit log. Each event includes the hash of the
+// previous one, so editing or deleting a line breaks the chain. The chain is
+// not keyed: it cannot detect events cut off the end of the log, or a log
+// whose hashes were all recomputed after editing.
+type Event struct {
+	Time        time.Time `json:"time" yaml:"time"`
+	Action      string    `json:"action" yaml:"action"`
+	AccountID   string    `json:"account_id,omitempty" yaml:"account_id,omitempty"`
+	AccountName string    `json:"account_name,omitempty" yaml:"account_name,omitempty"`
+	FromID      string    `json:"from_id,omitempty" yaml:"from_id,omitempty"`
+	FromName    string    `json:"from_name,omitempty" yaml:"from_name,omitempty"`
+	ToID        string    `json:"to_id,omitempty" yaml:"to_id,omitempty"`
+	ToName      string    `json:"to_name,omitempty" yaml:"to_name,omitempty"`
+	Cwd         string    `json:"cwd" yaml:"cwd"`
+	Hostname    string    `json:"hostname" yaml:"hostname"`
+	User        string    `json:"user" yaml:"user"`
+	Version     string    `json:"version" yaml:"version"`
+	PrevHash    string    `json:"prev_hash" yaml:"prev_hash"`
+	Hash        string    `json:"hash" yaml:"hash"`
+}
+
+// Involves returns true if the event concerns the given account ID
+func (e Event) Involves(accountID string) bool {
+	return e.AccountID == accountID || e.FromID == accountID || e.ToID == accountID
+}
+
+// Append fills in the environment and hash chain of an event and appends it
+// to the log. The log is locked while appending, so concurrent cl processes
+// don't fork the chain.
+func Append(e Event) error {
+	if err := config.EnsureConfigDirs(); err != nil {
+		return err
+	}
+
+	path, err := config.GetAuditLogPath()
+	if err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if err := lockFile(f); err != nil {
+		return fmt.Errorf("failed to lock audit log: %w", err)
+	}
+	defer unlockFile(f)
+
+	prevHash, partial, err := lastHash(f)
+	if err != nil {
+		return err
+	}
+
+	e.Time = time.Now().UTC()
+	e.Cwd, _ = os.Getwd()
+	e.Hostname, _ = os.Hostname()
+	if u, err := user.Current(); err == nil {
+		e.User = u.Username
+	}
+	e.PrevHash = prevHash
+	e.Hash, err = hashEvent(e)
+	if err != nil {
+		return err
+	}
+
+	line, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+	if partial {
+		// Keep a torn last line from swallowing this event
+		line = append([]byte{'\n'}, line...)
+	}
+
+	_, err = f.Write(line)
+	return err
+}
+
+// lastHash returns the hash of the last readable event in the log, reading
+// backwards from the end. Unreadable lines are skipped; partial reports
+// whether the log doesn't end in a newline.
+func lastHash(f *os.File) (hash string, partial bool, err error) {
+	info, err := f.Stat()
+	if err != nil {
+		return "", false, err
+	}
+
+	const chunkSize = 4096
+	end := info.Size()
+	var tail []byte
+	for offset := end; offset > 0; {
+		n := int64(chunkSize)
+		if offset < n {
+			n = offset
+		}
+		offset -= n
+		chunk := make([]byte, n)
+		if _, err := f.ReadAt(chunk, offset); err != nil {
+			return "", false, err
+		}
+		if offset+n == end {
+			partial = chunk[n-1] != '\n'
+		}
+		tail = append(chunk, tail...)
+
+		// Try every complete line in the buffer, newest first; the first one
+		// may be cut off unless we reached the start of the file
+		lines := strings.Split(string(tail), "\n")
+		first := 1
+		if offset == 0 {
+			first = 0
+		}
+		for i := len(lines) - 1; i >= first; i-- {
+			if e, ok := parseLine(lines[i]); ok {
+				return e.Hash, partial, nil
+			}
+		}
+		if offset > 0 {
+			tail = []byte(lines[0])
+		}
+	}
+	return "", partial, nil
+}
+
+// parseLine decodes one line of the log, reporting false for blank or unreadable lines
+func parseLine(line string) (Event, bool) {
+	line = strings.TrimSpace(line)
+	if line == "" {
+		return Event{}, false
+	}
+	var e Event
+	if err := json.Unmarshal([]byte(line), &e); err != nil {
+		return Event{}, false
+	}
+	return e, true
+}
+
+// Read returns all readable events in the audit log, oldest first
+func Read() ([]Event, error) {
+	events, _, err := ReadLog()
+	return events, err
+}
+
+// ReadLog returns all readable events in the audit log, oldest first, and the
+// line numbers of lines that could not be parsed and were skipped
+func ReadLog() (events []Event, skipped []int, err error) {
+	path, err := config.GetAuditLogPath()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := scanner.Text()
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		e, ok := parseLine(line)
+		if !ok {
+			skipped = append(skipped, lineNo)
+			continue
+		}
+		events = append(events, e)
+	}
+
+	return events, skipped, scanner.Err()
+}
+
+// Verify checks the hash chain and returns an error describing the first broken event
+func Verify(events []Event) error {
+	prevHash := ""
+	for i, e := range events {
+		if e.PrevHash != prevHash {
+			return fmt.Errorf("event %d (%s at %s): previous hash does not match; events were removed or reordered", i+1, e.Action, e.Time.Format(time.RFC3339))
+		}
+		expected, err := hashEvent(e)
+		if err != nil {
+			return err
+		}
+		if e.Hash != expected {
+			return fmt.Errorf("event %d (%s at %s): hash does not match; the event was modified", i+1, e.Action, e.Time.Format(time.RFC3339))
+		}
+		prevHash = e.Hash
+	}
+	return nil
+}
+
+// hashEvent returns the SHA256 of the event's JSON without its own hash
+func hashEvent(e Event) (string, error) {
+	e.Hash = ""
+	data, err := json.Marshal(e)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
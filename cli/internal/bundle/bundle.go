@@ -0,0 +1,166 @@
+// Package bundle encodes a single saved profile as a portable secret value
+// for restoring it in CI.
+package bundle
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/groo-dev/cl-wrangler/cli/internal/store"
+)
+
+const (
+	formatVersion = 1
+	encryption    = "aes-256-gcm+pbkdf2-sha256"
+	iterations    = 600000
+
+	// Limits on encrypted bundles, checked before the key is derived so a
+	// crafted value can neither weaken the key nor stall or crash decoding
+	minIterations = iterations
+	maxIterations = 10000000
+	minSaltSize   = 16
+	nonceSize     = 12 // GCM standard nonce
+)
+
+// ErrPassphraseRequired is returned when decoding an encrypted bundle without a passphrase
+var ErrPassphraseRequired = errors.New("bundle is encrypted; a passphrase is required")
+
+// Bundle is one profile and its wrangler config
+type Bundle struct {
+	Account store.Account `json:"account"`
+	Config  string        `json:"config"` // wrangler config TOML
+}
+
+// envelope is the JSON that gets base64-encoded
+type envelope struct {
+	Version    int             `json:"version"`
+	Bundle     json.RawMessage `json:"bundle,omitempty"`
+	Encryption string          `json:"encryption,omitempty"`
+	Iterations int             `json:"iterations,omitempty"`
+	Salt       []byte          `json:"salt,omitempty"`
+	Nonce      []byte          `json:"nonce,omitempty"`
+	Ciphertext []byte          `json:"ciphertext,omitempty"`
+}
+
+// New bundles an account with its config, leaving out local usage data
+func New(acc store.Account, config []byte) Bundle {
+	acc.ConfigHash = ""
+	acc.LastUsedAt = time.Time{}
+	acc.UseCount = 0
+	return Bundle{Account: acc, Config: string(config)}
+}
+
+// Encode returns the bundle as a single base64 line, encrypted if passphrase is set
+func Encode(b Bundle, passphrase string) (string, error) {
+	data, err := json.Marshal(b)
+	if err != nil {
+		return "", err
+	}
+
+	env := envelope{Version: formatVersion}
+	if passphrase == "" {
+		env.Bundle = data
+	} else {
+		env.Encryption = encryption
+		env.Iterations = iterations
+		env.Salt = make([]byte, 16)
+		if _, err := rand.Read(env.Salt); err != nil {
+			return "", err
+		}
+		gcm, err := newGCM(passphrase, env.Salt, env.Iterations)
+		if err != nil {
+			return "", err
+		}
+		env.Nonce = make([]byte, gcm.NonceSize())
+		if _, err := rand.Read(env.Nonce); err != nil {
+			return "", err
+		}
+		env.Ciphertext = gcm.Seal(nil, env.Nonce, data, nil)
+	}
+
+	out, err := json.Marshal(env)
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(out), nil
+}
+
+// Decode parses a value produced by Encode
+func Decode(value, passphrase string) (*Bundle, error) {
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(value))
+	if err != nil {
+		return nil, fmt.Errorf("bundle is not valid base64: %w", err)
+	}
+
+	var env envelope
+	if err := json.Unmarshal(raw, &env); err != nil {
+		return nil, fmt.Errorf("invalid bundle: %w", err)
+	}
+	if env.Version != formatVersion {
+		return nil, fmt.Errorf("unsupported bundle version %d", env.Version)
+	}
+
+	data := []byte(env.Bundle)
+	if env.Encryption != "" {
+		if env.Encryption != encryption {
+			return nil, fmt.Errorf("unsupported bundle encryption %q", env.Encryption)
+		}
+		if passphrase == "" {
+			return nil, ErrPassphraseRequired
+		}
+		if err := checkEncryption(env); err != nil {
+			return nil, err
+		}
+		gcm, err := newGCM(passphrase, env.Salt, env.Iterations)
+		if err != nil {
+			return nil, err
+		}
+		data, err = gcm.Open(nil, env.Nonce, env.Ciphertext, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt bundle: wrong passphrase or corrupted value")
+		}
+	}
+
+	var b Bundle
+	if err := json.Unmarshal(data, &b); err != nil {
+		return nil, fmt.Errorf("invalid bundle: %w", err)
+	}
+	if b.Account.ID == "" || b.Config == "" {
+		return nil, fmt.Errorf("invalid bundle: missing account or config")
+	}
+	return &b, nil
+}
+
+// checkEncryption validates the parameters of an encrypted bundle
+func checkEncryption(env envelope) error {
+	switch {
+	case len(env.Nonce) != nonceSize:
+		return fmt.Errorf("invalid bundle: nonce must be %d bytes", nonceSize)
+	case len(env.Salt) < minSaltSize:
+		return fmt.Errorf("invalid bundle: salt must be at least %d bytes", minSaltSize)
+	case env.Iterations < minIterations || env.Iterations > maxIterations:
+		return fmt.Errorf("invalid bundle: iterations must be between %d and %d", minIterations, maxIterations)
+	}
+	return nil
+}
+
+func newGCM(passphrase string, salt []byte, iter int) (cipher.AEAD, error) {
+	key, err := pbkdf2.Key(sha256.New, passphrase, salt, iter, 32)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,162 @@
+package bundle
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/groo-dev/cl-wrangler/cli/internal/store"
+)
+
+const testConfig = "oauth_token = \"abc\"\nrefresh_token = \"def\"\n"
+
+func testBundle() Bundle {
+	return New(store.Account{
+		ID:         "0123456789abcdef0123456789abcdef",
+		Name:       "dev",
+		Email:      "dev@example.com",
+		ConfigHash: "hash",
+		LastUsedAt: time.Now(),
+		UseCount:   3,
+	}, []byte(testConfig))
+}
+
+func mustDecodeBase64(t *testing.T, value string) []byte {
+	t.Helper()
+	raw, err := base64.StdEncoding.DecodeString(value)
+	if err != nil {
+		t.Fatalf("decode base64: %v", err)
+	}
+	return raw
+}
+
+// tamper decodes an encoded bundle's envelope, lets edit change it and
+// encodes it again
+func tamper(t *testing.T, value string, edit func(*envelope)) string {
+	t.Helper()
+	var env envelope
+	if err := json.Unmarshal(mustDecodeBase64(t, value), &env); err != nil {
+		t.Fatalf("decode envelope: %v", err)
+	}
+	edit(&env)
+	out, err := json.Marshal(env)
+	if err != nil {
+		t.Fatalf("encode envelope: %v", err)
+	}
+	return base64.StdEncoding.EncodeToString(out)
+}
+
+func TestNewDropsUsageData(t *testing.T) {
+	acc := testBundle().Account
+	if acc.ConfigHash != "" || !acc.LastUsedAt.IsZero() || acc.UseCount != 0 {
+		t.Errorf("usage data kept: %+v", acc)
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	for _, passphrase := range []string{"", "correct horse"} {
+		value, err := Encode(testBundle(), passphrase)
+		if err != nil {
+			t.Fatalf("Encode: %v", err)
+		}
+		if passphrase != "" && strings.Contains(string(mustDecodeBase64(t, value)), "oauth_token") {
+			t.Error("encrypted bundle contains the plain config")
+		}
+
+		b, err := Decode(" "+value+"\n", passphrase)
+		if err != nil {
+			t.Fatalf("Decode: %v", err)
+		}
+		if b.Account.ID != testBundle().Account.ID || b.Account.Name != "dev" || b.Config != testConfig {
+			t.Errorf("round trip = %+v", b)
+		}
+	}
+}
+
+func TestDecodeRequiresPassphrase(t *testing.T) {
+	value, err := Encode(testBundle(), "secret")
+	if err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	if _, err := Decode(value, ""); !errors.Is(err, ErrPassphraseRequired) {
+		t.Errorf("err = %v, want ErrPassphraseRequired", err)
+	}
+}
+
+func TestDecodeRejectsTampering(t *testing.T) {
+	value, err := Encode(testBundle(), "secret")
+	if err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+
+	for _, tt := range []struct {
+		name       string
+		value      string
+		passphrase string
+		want       string
+	}{
+		{
+			name:       "wrong passphrase",
+			value:      value,
+			passphrase: "guess",
+			want:       "wrong passphrase",
+		},
+		{
+			name:       "truncated nonce",
+			value:      tamper(t, value, func(env *envelope) { env.Nonce = env.Nonce[:8] }),
+			passphrase: "secret",
+			want:       "nonce must be",
+		},
+		{
+			name:       "short salt",
+			value:      tamper(t, value, func(env *envelope) { env.Salt = env.Salt[:4] }),
+			passphrase: "secret",
+			want:       "salt must be",
+		},
+		{
+			name:       "weakened iterations",
+			value:      tamper(t, value, func(env *envelope) { env.Iterations = 1 }),
+			passphrase: "secret",
+			want:       "iterations must be",
+		},
+		{
+			name:       "excessive iterations",
+			value:      tamper(t, value, func(env *envelope) { env.Iterations = maxIterations + 1 }),
+			passphrase: "secret",
+			want:       "iterations must be",
+		},
+		{
+			name:       "flipped ciphertext byte",
+			value:      tamper(t, value, func(env *envelope) { env.Ciphertext[0] ^= 0x01 }),
+			passphrase: "secret",
+			want:       "wrong passphrase or corrupted",
+		},
+		{
+			name:       "unknown encryption",
+			value:      tamper(t, value, func(env *envelope) { env.Encryption = "rot13" }),
+			passphrase: "secret",
+			want:       "unsupported bundle encryption",
+		},
+		{
+			name:       "unknown version",
+			value:      tamper(t, value, func(env *envelope) { env.Version = 2 }),
+			passphrase: "secret",
+			want:       "unsupported bundle version",
+		},
+		{
+			name:  "not base64",
+			value: "not base64!",
+			want:  "not valid base64",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Decode(tt.value, tt.passphrase)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("err = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
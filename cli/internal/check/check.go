@@ -0,0 +1,179 @@
+// Package check verifies saved profiles' credentials in parallel.
+package check
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/groo-dev/cl-wrangler/cli/internal/store"
+	"github.com/groo-dev/cl-wrangler/cli/internal/wrangler"
+)
+
+// Status is the outcome of checking one profile
+type Status string
+
+const (
+	StatusValid      Status = "valid"
+	StatusExpired    Status = "expired"
+	StatusRevoked    Status = "revoked"
+	StatusMismatched Status = "mismatched"
+	StatusLoggedOut  Status = "logged_out"
+	StatusError      Status = "error"
+)
+
+// defaultAPIBaseURL is overridden by CLOUDFLARE_API_BASE_URL, as in wrangler
+const defaultAPIBaseURL = "https://api.cloudflare.com/client/v4"
+
+// Target is a profile to check with the credentials read from its config
+type Target struct {
+	Account store.Account
+	Token   *store.TokenInfo
+	// Refresh renews an expired OAuth token; nil if it can't be refreshed here
+	Refresh func() (*store.TokenInfo, error)
+	// Cmd is the wrangler a wrangler probe runs for this profile
+	Cmd wrangler.Cmd
+}
+
+// Result is the outcome of checking one profile
+type Result struct {
+	Account  store.Account
+	Status   Status
+	Detail   string
+	Duration time.Duration
+}
+
+// Probe checks a profile's credentials against Cloudflare
+type Probe func(ctx context.Context, t Target) (Status, string)
+
+// Run checks targets with at most workers probes at a time, giving each
+// profile timeout. Results are in the order of targets.
+func Run(ctx context.Context, targets []Target, workers int, timeout time.Duration, probe Probe) []Result {
+	if workers < 1 {
+		workers = 1
+	}
+
+	results := make([]Result, len(targets))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i] = checkOne(ctx, targets[i], timeout, probe)
+			}
+		}()
+	}
+
+	for i := range targets {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	return results
+}
+
+func checkOne(ctx context.Context, t Target, timeout time.Duration, probe Probe) Result {
+	start := time.Now()
+	status, detail := classify(ctx, t, timeout, probe)
+	return Result{Account: t.Account, Status: status, Detail: detail, Duration: time.Since(start)}
+}
+
+// classify settles what can be told from the saved config and probes the rest
+func classify(ctx context.Context, t Target, timeout time.Duration, probe Probe) (Status, string) {
+	switch {
+	case t.Account.LoggedOut:
+		return StatusLoggedOut, ""
+	case t.Token == nil || t.Token.Token == "":
+		return StatusError, "no token in saved config"
+	case !t.Token.ExpiresAt.IsZero() && time.Now().After(t.Token.ExpiresAt):
+		// An expired OAuth token only counts as expired if it can't be refreshed
+		detail := "expired " + t.Token.ExpiresAt.Local().Format("2006-01-02 15:04")
+		if t.Token.Type != store.TokenTypeOAuth || t.Token.RefreshToken == "" || t.Refresh == nil {
+			return StatusExpired, detail
+		}
+		token, err := t.Refresh()
+		if err != nil {
+			return StatusExpired, fmt.Sprintf("%s; refresh failed: %v", detail, err)
+		}
+		t.Token = token
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	status, detail := probe(ctx, t)
+	if status == StatusError && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		detail = fmt.Sprintf("timed out after %s", timeout)
+	}
+	return status, detail
+}
+
+// APIProbe lists the accounts the token can access through the Cloudflare
+// API and looks for the profile's account ID
+func APIProbe(ctx context.Context, t Target) (Status, string) {
+	baseURL := os.Getenv("CLOUDFLARE_API_BASE_URL")
+	if baseURL == "" {
+		baseURL = defaultAPIBaseURL
+	}
+	baseURL = strings.TrimSuffix(baseURL, "/")
+
+	for page := 1; ; page++ {
+		query := url.Values{"page": {fmt.Sprint(page)}, "per_page": {"50"}}
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, baseURL+"/accounts?"+query.Encode(), nil)
+		if err != nil {
+			return StatusError, err.Error()
+		}
+		if t.Token.Type == store.TokenTypeAPIKey {
+			req.Header.Set("X-Auth-Email", t.Account.Email)
+			req.Header.Set("X-Auth-Key", t.Token.Token)
+		} else {
+			req.Header.Set("Authorization", "Bearer "+t.Token.Token)
+		}
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return StatusError, err.Error()
+		}
+
+		var body struct {
+			Result []struct {
+				ID   string `json:"id"`
+				Name string `json:"name"`
+			} `json:"result"`
+			ResultInfo struct {
+				TotalPages int `json:"total_pages"`
+			} `json:"result_info"`
+		}
+		err = json.NewDecoder(resp.Body).Decode(&body)
+		resp.Body.Close()
+
+		switch {
+		case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+			return StatusRevoked, fmt.Sprintf("token rejected (HTTP %d)", resp.StatusCode)
+		case resp.StatusCode != http.StatusOK:
+			return StatusError, fmt.Sprintf("unexpected HTTP %d", resp.StatusCode)
+		case err != nil:
+			return StatusError, fmt.Sprintf("invalid API response: %v", err)
+		}
+
+		for _, acc := range body.Result {
+			if acc.ID == t.Account.ID {
+				return StatusValid, ""
+			}
+		}
+		if page >= body.ResultInfo.TotalPages {
+			return StatusMismatched, fmt.Sprintf("token works but can't access account %s", t.Account.ID)
+		}
+	}
+}
@@ -23,6 +23,15 @@ func GetAccountsDir() (string, error) {
 	return filepath.Join(configDir, "accounts"), nil
 }
 
+// GetAccountConfigPath returns the path to a saved account's wrangler config
+func GetAccountConfigPath(accountID string) (string, error) {
+	accountsDir, err := GetAccountsDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(accountsDir, accountID+".toml"), nil
+}
+
 // GetAccountsDBPath returns the path to accounts.json
 func GetAccountsDBPath() (string, error) {
 	configDir, err := GetConfigDir()
@@ -32,6 +41,33 @@ func GetAccountsDBPath() (string, error) {
 	return filepath.Join(configDir, "accounts.json"), nil
 }
 
+// GetAuditLogPath returns the path to the audit log of account operations
+func GetAuditLogPath() (string, error) {
+	configDir, err := GetConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, "audit.jsonl"), nil
+}
+
+// GetCIStatePath returns the path to the record of files written by 'cl ci-restore'
+func GetCIStatePath() (string, error) {
+	configDir, err := GetConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, "ci-restore.json"), nil
+}
+
+// GetHooksDir returns the directory where hook executables are stored
+func GetHooksDir() (string, error) {
+	configDir, err := GetConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, "hooks"), nil
+}
+
 // GetWranglerConfigPath returns the path to wrangler's default.toml
 // On macOS, wrangler uses ~/Library/Preferences/.wrangler/config/default.toml
 func GetWranglerConfigPath() (string, error) {
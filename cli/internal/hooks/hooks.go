@@ -0,0 +1,139 @@
+package hooks
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+
+	"github.com/groo-dev/cl-wrangler/cli/internal/config"
+	"github.com/groo-dev/cl-wrangler/cli/internal/store"
+)
+
+// Hook names, used both as file names in the hooks directory and as keys in settings
+const (
+	PreSwitch  = "pre-switch"
+	PostSwitch = "post-switch"
+)
+
+// Event describes a change of the current account
+type Event struct {
+	Hook    string         // PreSwitch or PostSwitch
+	Command string         // cl command that triggered the change
+	Old     *store.Account // nil if there was no current account
+	New     *store.Account // nil if no account is current afterwards
+}
+
+// Run executes the hooks for an event: first <config>/hooks/<hook> (a single
+// executable or a directory of executables run in name order), then any
+// commands configured in settings. It stops at the first failing hook.
+func Run(db *store.AccountsDB, event Event) error {
+	paths, err := hookExecutables(event.Hook)
+	if err != nil {
+		return err
+	}
+
+	env := append(os.Environ(), event.env()...)
+
+	for _, path := range paths {
+		if err := run(exec.Command(path), env); err != nil {
+			return fmt.Errorf("%s hook %s: %w", event.Hook, path, err)
+		}
+	}
+
+	for _, command := range db.Settings.Hooks[event.Hook] {
+		if err := run(shellCommand(command), env); err != nil {
+			return fmt.Errorf("%s hook '%s': %w", event.Hook, command, err)
+		}
+	}
+
+	return nil
+}
+
+func (e Event) env() []string {
+	env := []string{
+		"CL_HOOK=" + e.Hook,
+		"CL_COMMAND=" + e.Command,
+	}
+	env = append(env, accountEnv("CL_OLD", e.Old)...)
+	env = append(env, accountEnv("CL_NEW", e.New)...)
+	return env
+}
+
+func accountEnv(prefix string, acc *store.Account) []string {
+	var id, name, email string
+	if acc != nil {
+		id, name, email = acc.ID, acc.Name, acc.Email
+	}
+	return []string{
+		prefix + "_ACCOUNT_ID=" + id,
+		prefix + "_ACCOUNT_NAME=" + name,
+		prefix + "_ACCOUNT_EMAIL=" + email,
+	}
+}
+
+// hookExecutables returns the executables for a hook in the hooks directory
+func hookExecutables(hook string) ([]string, error) {
+	hooksDir, err := config.GetHooksDir()
+	if err != nil {
+		return nil, err
+	}
+
+	path := filepath.Join(hooksDir, hook)
+	info, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if !info.IsDir() {
+		if !isExecutable(info) {
+			return nil, nil
+		}
+		return []string{path}, nil
+	}
+
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var paths []string
+	for _, entry := range entries {
+		entryInfo, err := entry.Info()
+		if err != nil || entryInfo.IsDir() || !isExecutable(entryInfo) {
+			continue
+		}
+		paths = append(paths, filepath.Join(path, entry.Name()))
+	}
+	sort.Strings(paths)
+
+	return paths, nil
+}
+
+func isExecutable(info os.FileInfo) bool {
+	if runtime.GOOS == "windows" {
+		return true
+	}
+	return info.Mode()&0111 != 0
+}
+
+func shellCommand(command string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return exec.Command("cmd", "/C", command)
+	}
+	return exec.Command("sh", "-c", command)
+}
+
+// run executes a hook, sending its output to stderr so it never mixes with command output
+func run(cmd *exec.Cmd, env []string) error {
+	cmd.Env = env
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stderr
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
@@ -0,0 +1,156 @@
+package manifest
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/groo-dev/cl-wrangler/cli/internal/store"
+	"gopkg.in/yaml.v3"
+)
+
+// Account is a team account listed in a manifest
+type Account struct {
+	ID    string   `json:"id" yaml:"id"`
+	Name  string   `json:"name" yaml:"name"`
+	Email string   `json:"email,omitempty" yaml:"email,omitempty"`
+	Tags  []string `json:"tags,omitempty" yaml:"tags,omitempty"`
+}
+
+// Manifest is a versionable list of the accounts a team works with
+type Manifest struct {
+	Accounts []Account `json:"accounts" yaml:"accounts"`
+}
+
+// Load reads a YAML or JSON manifest
+func Load(path string) (*Manifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var m Manifest
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		err = json.Unmarshal(data, &m)
+	} else {
+		err = yaml.Unmarshal(data, &m)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	if err := m.validate(); err != nil {
+		return nil, fmt.Errorf("invalid manifest %s: %w", path, err)
+	}
+	return &m, nil
+}
+
+func (m *Manifest) validate() error {
+	seen := make(map[string]bool)
+	for i, acc := range m.Accounts {
+		if acc.ID == "" {
+			return fmt.Errorf("account #%d has no id", i+1)
+		}
+		if acc.Name == "" {
+			return fmt.Errorf("account %s has no name", acc.ID)
+		}
+		if seen[acc.ID] {
+			return fmt.Errorf("account %s is listed twice", acc.ID)
+		}
+		seen[acc.ID] = true
+	}
+	return nil
+}
+
+// Get finds a manifest account by ID
+func (m *Manifest) Get(id string) *Account {
+	for i := range m.Accounts {
+		if m.Accounts[i].ID == id {
+			return &m.Accounts[i]
+		}
+	}
+	return nil
+}
+
+// Rename is a local profile whose name differs from the manifest
+type Rename struct {
+	ID      string
+	OldName string
+	NewName string
+}
+
+// EmailMismatch is a local profile logged in with an unexpected email
+type EmailMismatch struct {
+	ID       string
+	Name     string
+	Expected string
+	Actual   string
+}
+
+// Plan describes how local profiles differ from a manifest
+type Plan struct {
+	Missing         []Account       // in the manifest but not saved locally
+	Renames         []Rename        // saved under a different name
+	Retags          []string        // IDs of saved profiles missing manifest tags
+	Unknown         []store.Account // saved locally but not in the manifest
+	EmailMismatches []EmailMismatch // logged in with a different email than expected
+}
+
+// Reconcile compares a manifest with the local accounts
+func Reconcile(m *Manifest, db *store.AccountsDB) Plan {
+	var plan Plan
+
+	for _, acc := range m.Accounts {
+		local := db.GetAccount(acc.ID)
+		if local == nil {
+			plan.Missing = append(plan.Missing, acc)
+			continue
+		}
+		if local.Name != acc.Name {
+			plan.Renames = append(plan.Renames, Rename{ID: acc.ID, OldName: local.Name, NewName: acc.Name})
+		}
+		if len(MissingTags(local.Tags, acc.Tags)) > 0 {
+			plan.Retags = append(plan.Retags, acc.ID)
+		}
+		if acc.Email != "" && !strings.EqualFold(local.Email, acc.Email) {
+			plan.EmailMismatches = append(plan.EmailMismatches, EmailMismatch{
+				ID:       acc.ID,
+				Name:     acc.Name,
+				Expected: acc.Email,
+				Actual:   local.Email,
+			})
+		}
+	}
+
+	for _, local := range db.Accounts {
+		if m.Get(local.ID) == nil {
+			plan.Unknown = append(plan.Unknown, local)
+		}
+	}
+
+	return plan
+}
+
+// Apply gives a local profile the manifest's canonical name and tags
+func (acc Account) Apply(local *store.Account) {
+	local.Name = acc.Name
+	for _, tag := range acc.Tags {
+		if !slices.Contains(local.Tags, tag) {
+			local.Tags = append(local.Tags, tag)
+		}
+	}
+}
+
+// MissingTags returns the tags in want that have lacks
+func MissingTags(have, want []string) []string {
+	var missing []string
+	for _, w := range want {
+		if !slices.Contains(have, w) {
+			missing = append(missing, w)
+		}
+	}
+	return missing
+}
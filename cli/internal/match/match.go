@@ -0,0 +1,131 @@
+package match
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/groo-dev/cl-wrangler/cli/internal/store"
+	"github.com/sahilm/fuzzy"
+)
+
+// Rank orders how strongly a query matches an account
+type Rank int
+
+const (
+	RankFuzzy Rank = iota + 1
+	RankPrefix
+	RankExactName // exact display name or alias
+	RankExactID
+)
+
+// fuzzyMargin is how far apart two fuzzy scores must be for the best one to be picked
+const fuzzyMargin = 10
+
+// Candidate is an account matching a query
+type Candidate struct {
+	Account store.Account
+	Rank    Rank
+	Score   int // fuzzy score, only meaningful for RankFuzzy
+}
+
+// Find returns the accounts matching a query, best first. Accounts should be
+// passed in preference order (e.g. most recently used first) to break ties.
+// With exact set, only exact IDs, names and aliases match.
+func Find(accounts []store.Account, query string, exact bool) []Candidate {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil
+	}
+
+	var candidates []Candidate
+	unmatched := make([]store.Account, 0, len(accounts))
+
+	for _, acc := range accounts {
+		rank := rankAccount(acc, query)
+		if rank == 0 || (exact && rank == RankPrefix) {
+			unmatched = append(unmatched, acc)
+			continue
+		}
+		candidates = append(candidates, Candidate{Account: acc, Rank: rank})
+	}
+
+	if !exact {
+		// fuzzy's ordering of equal scores isn't stable; restore preference
+		// order so the sort below breaks ties by it
+		matches := fuzzy.FindFrom(query, searchable(unmatched))
+		sort.Slice(matches, func(i, j int) bool { return matches[i].Index < matches[j].Index })
+		for _, m := range matches {
+			candidates = append(candidates, Candidate{Account: unmatched[m.Index], Rank: RankFuzzy, Score: m.Score})
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].Rank != candidates[j].Rank {
+			return candidates[i].Rank > candidates[j].Rank
+		}
+		return candidates[i].Score > candidates[j].Score
+	})
+
+	return candidates
+}
+
+// Best returns the candidates that are too close to the best one to tell apart.
+// A single result means the match is unambiguous.
+func Best(candidates []Candidate) []Candidate {
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	top := candidates[0]
+	best := []Candidate{top}
+	for _, c := range candidates[1:] {
+		if c.Rank != top.Rank {
+			break
+		}
+		if top.Rank == RankFuzzy && top.Score-c.Score >= fuzzyMargin {
+			break
+		}
+		best = append(best, c)
+	}
+	return best
+}
+
+// Label describes a candidate for ambiguity prompts and errors
+func (c Candidate) Label() string {
+	return fmt.Sprintf("%s (%s) %s", c.Account.Name, c.Account.Email, c.Account.ID)
+}
+
+func rankAccount(acc store.Account, query string) Rank {
+	if acc.ID == query {
+		return RankExactID
+	}
+	// Names and aliases both ignore case
+	if strings.EqualFold(acc.Name, query) || acc.HasAlias(query) {
+		return RankExactName
+	}
+
+	lower := strings.ToLower(query)
+	if strings.HasPrefix(strings.ToLower(acc.ID), lower) || strings.HasPrefix(strings.ToLower(acc.Name), lower) {
+		return RankPrefix
+	}
+	for _, alias := range acc.Aliases {
+		if strings.HasPrefix(strings.ToLower(alias), lower) {
+			return RankPrefix
+		}
+	}
+
+	return 0
+}
+
+// searchable implements fuzzy.Source for accounts
+type searchable []store.Account
+
+func (s searchable) String(i int) string {
+	acc := s[i]
+	return fmt.Sprintf("%s %s %s %s", acc.Name, strings.Join(acc.Aliases, " "), acc.Email, acc.ID)
+}
+
+func (s searchable) Len() int {
+	return len(s)
+}
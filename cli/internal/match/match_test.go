@@ -0,0 +1,161 @@
+package match
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/groo-dev/cl-wrangler/cli/internal/store"
+)
+
+func ids(candidates []Candidate) []string {
+	var out []string
+	for _, c := range candidates {
+		out = append(out, c.Account.ID)
+	}
+	return out
+}
+
+func TestFindRanking(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		accounts []store.Account
+		query    string
+		wantBest []string
+		wantRank Rank
+	}{
+		{
+			name: "exact ID beats exact name",
+			accounts: []store.Account{
+				{ID: "1111", Name: "cafe"},
+				{ID: "cafe", Name: "other"},
+			},
+			query:    "cafe",
+			wantBest: []string{"cafe"},
+			wantRank: RankExactID,
+		},
+		{
+			name: "exact alias beats prefix",
+			accounts: []store.Account{
+				{ID: "1111", Name: "production"},
+				{ID: "2222", Name: "acme-live", Aliases: []string{"prod"}},
+			},
+			query:    "prod",
+			wantBest: []string{"2222"},
+			wantRank: RankExactName,
+		},
+		{
+			name: "names and aliases ignore case alike",
+			accounts: []store.Account{
+				{ID: "1111", Name: "production"},
+				{ID: "2222", Name: "acme-live", Aliases: []string{"prod"}},
+			},
+			query:    "PROD",
+			wantBest: []string{"2222"},
+			wantRank: RankExactName,
+		},
+		{
+			name: "exact name beats prefix",
+			accounts: []store.Account{
+				{ID: "1111", Name: "acme-dev"},
+				{ID: "2222", Name: "acme"},
+			},
+			query:    "Acme",
+			wantBest: []string{"2222"},
+			wantRank: RankExactName,
+		},
+		{
+			name: "prefix beats fuzzy",
+			accounts: []store.Account{
+				{ID: "1111", Name: "my-acme"},
+				{ID: "2222", Name: "acme-dev"},
+			},
+			query:    "acm",
+			wantBest: []string{"2222"},
+			wantRank: RankPrefix,
+		},
+		{
+			name: "several prefixes are ambiguous",
+			accounts: []store.Account{
+				{ID: "1111", Name: "acme-dev"},
+				{ID: "2222", Name: "acme-prod"},
+			},
+			query:    "acme-",
+			wantBest: []string{"1111", "2222"},
+			wantRank: RankPrefix,
+		},
+		{
+			name: "fuzzy match",
+			accounts: []store.Account{
+				{ID: "1111", Name: "staging"},
+				{ID: "2222", Name: "acme-production"},
+			},
+			query:    "aprod",
+			wantBest: []string{"2222"},
+			wantRank: RankFuzzy,
+		},
+		{
+			name: "fuzzy scores within the margin are ambiguous",
+			accounts: []store.Account{
+				{ID: "1111", Name: "x-prod-one"},
+				{ID: "2222", Name: "x-prod-two"},
+			},
+			query:    "prd",
+			wantBest: []string{"1111", "2222"},
+			wantRank: RankFuzzy,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			best := Best(Find(tt.accounts, tt.query, false))
+			if got := ids(best); !slices.Equal(got, tt.wantBest) {
+				t.Fatalf("Best(%q) = %v, want %v", tt.query, got, tt.wantBest)
+			}
+			for _, c := range best {
+				if c.Rank != tt.wantRank {
+					t.Errorf("%s ranked %d, want %d", c.Account.ID, c.Rank, tt.wantRank)
+				}
+			}
+		})
+	}
+}
+
+func TestBestFuzzyMargin(t *testing.T) {
+	candidates := []Candidate{
+		{Account: store.Account{ID: "1111"}, Rank: RankFuzzy, Score: 30},
+		{Account: store.Account{ID: "2222"}, Rank: RankFuzzy, Score: 30 - fuzzyMargin + 1},
+		{Account: store.Account{ID: "3333"}, Rank: RankFuzzy, Score: 30 - fuzzyMargin},
+	}
+	if got := ids(Best(candidates)); !slices.Equal(got, []string{"1111", "2222"}) {
+		t.Errorf("Best = %v, want the two scores within %d", got, fuzzyMargin)
+	}
+
+	clear := []Candidate{candidates[0], candidates[2]}
+	if got := ids(Best(clear)); !slices.Equal(got, []string{"1111"}) {
+		t.Errorf("Best = %v, want only the clear winner", got)
+	}
+}
+
+func TestFindExact(t *testing.T) {
+	accounts := []store.Account{
+		{ID: "1111", Name: "acme-dev", Aliases: []string{"dev"}},
+		{ID: "2222", Name: "acme-production"},
+	}
+
+	for _, query := range []string{"acme", "aprod"} {
+		if got := Find(accounts, query, true); len(got) != 0 {
+			t.Errorf("Find(%q, exact) = %v, want no prefix or fuzzy matches", query, ids(got))
+		}
+	}
+
+	for query, want := range map[string]string{"DEV": "1111", "acme-production": "2222", "2222": "2222"} {
+		got := Best(Find(accounts, query, true))
+		if len(got) != 1 || got[0].Account.ID != want {
+			t.Errorf("Find(%q, exact) = %v, want %s", query, ids(got), want)
+		}
+	}
+}
+
+func TestFindEmptyQuery(t *testing.T) {
+	if got := Find([]store.Account{{ID: "1111", Name: "dev"}}, "  ", false); got != nil {
+		t.Errorf("Find of a blank query = %v, want nil", ids(got))
+	}
+}
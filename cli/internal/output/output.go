@@ -0,0 +1,110 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Format is a machine-readable output format selected with --output
+type Format string
+
+const (
+	FormatTable Format = "table"
+	FormatJSON  Format = "json"
+	FormatYAML  Format = "yaml"
+	FormatTSV   Format = "tsv"
+)
+
+// Formats lists the supported output formats
+var Formats = []Format{FormatTable, FormatJSON, FormatYAML, FormatTSV}
+
+// Tabular is implemented by values that can be written as TSV
+type Tabular interface {
+	Header() []string
+	Rows() [][]string
+}
+
+// ParseFormat validates an --output value
+func ParseFormat(s string) (Format, error) {
+	for _, f := range Formats {
+		if string(f) == s {
+			return f, nil
+		}
+	}
+	return "", fmt.Errorf("unknown output format %q (expected one of: table, json, yaml, tsv)", s)
+}
+
+// Write renders v in the given format. FormatTable is handled by the caller.
+func Write(w io.Writer, format Format, v any) error {
+	switch format {
+	case FormatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	case FormatYAML:
+		enc := yaml.NewEncoder(w)
+		enc.SetIndent(2)
+		if err := enc.Encode(v); err != nil {
+			return err
+		}
+		return enc.Close()
+	case FormatTSV:
+		t, ok := v.(Tabular)
+		if !ok {
+			return fmt.Errorf("tsv output is not supported for this command")
+		}
+		return writeTSV(w, t)
+	}
+	return fmt.Errorf("unsupported output format: %s", format)
+}
+
+// WriteTemplate executes a Go template against the JSON form of v,
+// so fields are addressed by their JSON keys (e.g. {{.name}})
+func WriteTemplate(w io.Writer, tmpl string, v any) error {
+	t, err := template.New("output").Parse(tmpl)
+	if err != nil {
+		return fmt.Errorf("invalid template: %w", err)
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	var generic any
+	if err := json.Unmarshal(data, &generic); err != nil {
+		return err
+	}
+
+	var sb strings.Builder
+	if err := t.Execute(&sb, generic); err != nil {
+		return fmt.Errorf("failed to execute template: %w", err)
+	}
+
+	out := sb.String()
+	if !strings.HasSuffix(out, "\n") {
+		out += "\n"
+	}
+	_, err = io.WriteString(w, out)
+	return err
+}
+
+func writeTSV(w io.Writer, t Tabular) error {
+	if _, err := fmt.Fprintln(w, strings.Join(t.Header(), "\t")); err != nil {
+		return err
+	}
+	for _, row := range t.Rows() {
+		cells := make([]string, len(row))
+		for i, cell := range row {
+			cells[i] = strings.NewReplacer("\t", " ", "\n", " ").Replace(cell)
+		}
+		if _, err := fmt.Fprintln(w, strings.Join(cells, "\t")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
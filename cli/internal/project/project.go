@@ -0,0 +1,194 @@
+// Package project reads the account a wrangler project deploys to from its
+// wrangler.json, wrangler.jsonc or wrangler.toml.
+package project
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// configFiles are the wrangler config files, in the order wrangler prefers them
+var configFiles = []string{"wrangler.json", "wrangler.jsonc", "wrangler.toml"}
+
+// FindConfig walks up from dir to the nearest wrangler config file
+func FindConfig(dir string) (string, bool) {
+	for {
+		for _, name := range configFiles {
+			path := filepath.Join(dir, name)
+			if stat, err := os.Stat(path); err == nil && !stat.IsDir() {
+				return path, true
+			}
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", false
+		}
+		dir = parent
+	}
+}
+
+// AccountID returns the account_id a config file sets for env, falling back
+// to the top-level one as wrangler does. env may be empty. It returns "" if
+// the config doesn't set an account.
+func AccountID(path, env string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+
+	var top string
+	envIDs := map[string]string{}
+	if strings.HasSuffix(path, ".toml") {
+		top, envIDs, err = parseTOML(data)
+	} else {
+		top, envIDs, err = parseJSONC(data)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	if id := envIDs[env]; env != "" && id != "" {
+		return id, nil
+	}
+	return top, nil
+}
+
+// parseJSONC reads account_id from JSON with comments and trailing commas
+func parseJSONC(data []byte) (string, map[string]string, error) {
+	var config struct {
+		AccountID string `json:"account_id"`
+		Env       map[string]struct {
+			AccountID string `json:"account_id"`
+		} `json:"env"`
+	}
+	if err := json.Unmarshal(stripJSONC(data), &config); err != nil {
+		return "", nil, err
+	}
+
+	envIDs := map[string]string{}
+	for name, env := range config.Env {
+		envIDs[name] = env.AccountID
+	}
+	return config.AccountID, envIDs, nil
+}
+
+// stripJSONC removes comments and trailing commas, leaving strings alone
+func stripJSONC(data []byte) []byte {
+	var out bytes.Buffer
+	inString := false
+	for i := 0; i < len(data); i++ {
+		c := data[i]
+		switch {
+		case inString:
+			out.WriteByte(c)
+			if c == '\\' && i+1 < len(data) {
+				i++
+				out.WriteByte(data[i])
+			} else if c == '"' {
+				inString = false
+			}
+		case c == '"':
+			inString = true
+			out.WriteByte(c)
+		case c == '/' && i+1 < len(data) && data[i+1] == '/':
+			for i < len(data) && data[i] != '\n' {
+				i++
+			}
+			out.WriteByte('\n')
+		case c == '/' && i+1 < len(data) && data[i+1] == '*':
+			i += 2
+			for i+1 < len(data) && !(data[i] == '*' && data[i+1] == '/') {
+				i++
+			}
+			i++
+		case c == ',':
+			// Drop the comma if only whitespace and comments lead to a closing bracket
+			if next := nextToken(data, i+1); next == '}' || next == ']' {
+				continue
+			}
+			out.WriteByte(c)
+		default:
+			out.WriteByte(c)
+		}
+	}
+	return out.Bytes()
+}
+
+// nextToken returns the first byte from i that isn't whitespace or in a comment
+func nextToken(data []byte, i int) byte {
+	for i < len(data) {
+		switch {
+		case data[i] == ' ' || data[i] == '\t' || data[i] == '\r' || data[i] == '\n':
+			i++
+		case bytes.HasPrefix(data[i:], []byte("//")):
+			for i < len(data) && data[i] != '\n' {
+				i++
+			}
+		case bytes.HasPrefix(data[i:], []byte("/*")):
+			end := bytes.Index(data[i+2:], []byte("*/"))
+			if end < 0 {
+				return 0
+			}
+			i += end + 4
+		default:
+			return data[i]
+		}
+	}
+	return 0
+}
+
+// parseTOML reads the top-level and [env.<name>] account_id keys. It handles
+// the subset of TOML wrangler configs use for this key: a quoted string on
+// its own line.
+func parseTOML(data []byte) (string, map[string]string, error) {
+	var top string
+	envIDs := map[string]string{}
+	table := ""
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") {
+			table = strings.Trim(line, "[] \t")
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok || strings.TrimSpace(key) != "account_id" {
+			continue
+		}
+		id, err := tomlString(strings.TrimSpace(value))
+		if err != nil {
+			return "", nil, err
+		}
+
+		switch {
+		case table == "":
+			top = id
+		case strings.HasPrefix(table, "env.") && strings.Count(table, ".") == 1:
+			envIDs[strings.Trim(strings.TrimPrefix(table, "env."), `"'`)] = id
+		}
+	}
+	return top, envIDs, scanner.Err()
+}
+
+// tomlString unquotes a basic or literal TOML string, dropping a trailing comment
+func tomlString(value string) (string, error) {
+	if len(value) < 2 || (value[0] != '"' && value[0] != '\'') {
+		return "", fmt.Errorf("account_id must be a string, got %s", value)
+	}
+	end := strings.IndexByte(value[1:], value[0])
+	if end < 0 {
+		return "", fmt.Errorf("unterminated string %s", value)
+	}
+	return value[1 : end+1], nil
+}
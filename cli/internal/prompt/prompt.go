@@ -0,0 +1,63 @@
+package prompt
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/charmbracelet/huh"
+	"github.com/mattn/go-isatty"
+)
+
+var (
+	assumeYes bool
+	noInput   bool
+)
+
+// Configure sets the global prompt behaviour from the --yes and --no-input flags
+func Configure(yes, disableInput bool) {
+	assumeYes = yes
+	noInput = disableInput
+}
+
+// Interactive returns true if prompts may be shown to the user.
+// Prompting is disabled by --no-input or when stdin is not a terminal.
+func Interactive() bool {
+	if noInput {
+		return false
+	}
+	fd := os.Stdin.Fd()
+	return isatty.IsTerminal(fd) || isatty.IsCygwinTerminal(fd)
+}
+
+// AssumeYes returns true if confirmations should be answered automatically
+func AssumeYes() bool {
+	return assumeYes
+}
+
+// Require returns an error naming the flag or argument that must be supplied
+// when a prompt would be needed but input is disabled
+func Require(what string) error {
+	if Interactive() {
+		return nil
+	}
+	return fmt.Errorf("input required but prompting is disabled (no TTY or --no-input); %s", what)
+}
+
+// Confirm asks a yes/no question.
+// With --yes it returns true without prompting; without a TTY it fails asking for --yes.
+func Confirm(title string) (bool, error) {
+	if assumeYes {
+		return true, nil
+	}
+	if err := Require("pass --yes to confirm"); err != nil {
+		return false, err
+	}
+
+	var confirm bool
+	err := huh.NewConfirm().
+		Title(title).
+		Value(&confirm).
+		Run()
+
+	return confirm, err
+}
@@ -0,0 +1,124 @@
+// Package shellenv writes environment variables in shell and CI formats.
+package shellenv
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Format is an environment output format selected with --format
+type Format string
+
+const (
+	FormatBash       Format = "bash"
+	FormatZsh        Format = "zsh"
+	FormatFish       Format = "fish"
+	FormatPowerShell Format = "powershell"
+	FormatDotenv     Format = "dotenv"
+	FormatGitHub     Format = "github"
+	FormatJSON       Format = "json"
+)
+
+// Formats lists the supported formats
+var Formats = []Format{FormatBash, FormatZsh, FormatFish, FormatPowerShell, FormatDotenv, FormatGitHub, FormatJSON}
+
+// Var is one environment variable
+type Var struct {
+	Name  string
+	Value string
+}
+
+// ParseFormat validates a --format value
+func ParseFormat(s string) (Format, error) {
+	for _, f := range Formats {
+		if string(f) == s {
+			return f, nil
+		}
+	}
+	names := make([]string, len(Formats))
+	for i, f := range Formats {
+		names[i] = string(f)
+	}
+	return "", fmt.Errorf("unknown format %q (expected one of: %s)", s, strings.Join(names, ", "))
+}
+
+// Write renders vars in the given format
+func Write(w io.Writer, format Format, vars []Var) error {
+	if format == FormatJSON {
+		values := make(map[string]string, len(vars))
+		for _, v := range vars {
+			values[v.Name] = v.Value
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(values)
+	}
+
+	for _, v := range vars {
+		var line string
+		switch format {
+		case FormatBash, FormatZsh:
+			line = fmt.Sprintf("export %s=%s", v.Name, posixQuote(v.Value))
+		case FormatFish:
+			line = fmt.Sprintf("set -gx %s %s", v.Name, fishQuote(v.Value))
+		case FormatPowerShell:
+			line = fmt.Sprintf("$env:%s = '%s'", v.Name, strings.ReplaceAll(v.Value, "'", "''"))
+		case FormatDotenv:
+			line = fmt.Sprintf("%s=%s", v.Name, dotenvQuote(v.Value))
+		case FormatGitHub:
+			// $GITHUB_ENV takes NAME=value lines, and a delimiter block for multiline values
+			if strings.Contains(v.Value, "\n") {
+				delimiter, err := githubDelimiter(v.Value)
+				if err != nil {
+					return err
+				}
+				line = fmt.Sprintf("%s<<%s\n%s\n%s", v.Name, delimiter, v.Value, delimiter)
+			} else {
+				line = v.Name + "=" + v.Value
+			}
+		default:
+			return fmt.Errorf("unknown format %q", format)
+		}
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// githubDelimiter returns a random heredoc delimiter for $GITHUB_ENV, so a
+// value can't end its block early and inject other variables
+func githubDelimiter(value string) (string, error) {
+	for {
+		b := make([]byte, 16)
+		if _, err := rand.Read(b); err != nil {
+			return "", err
+		}
+		delimiter := "CL_EOF_" + hex.EncodeToString(b)
+		if !strings.Contains(value, delimiter) {
+			return delimiter, nil
+		}
+	}
+}
+
+func posixQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+func fishQuote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	return "'" + strings.ReplaceAll(s, "'", `\'`) + "'"
+}
+
+func dotenvQuote(s string) string {
+	if s != "" && !strings.ContainsAny(s, " \t\n\"'\\#$=") {
+		return s
+	}
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	return `"` + strings.ReplaceAll(s, "\n", `\n`) + `"`
+}
@@ -7,28 +7,80 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/groo-dev/cl-wrangler/cli/internal/config"
 )
 
 type Account struct {
-	ID         string    `json:"id"`
-	Name       string    `json:"name"`
-	Email      string    `json:"email"`
-	AddedAt    time.Time `json:"added_at"`
-	ConfigHash string    `json:"config_hash,omitempty"`
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`                   // display name, kept across re-logins
+	AccountName string    `json:"account_name,omitempty"` // name reported by Cloudflare
+	Email       string    `json:"email"`
+	AddedAt     time.Time `json:"added_at"`
+	ConfigHash  string    `json:"config_hash,omitempty"`
+	LastUsedAt  time.Time `json:"last_used_at,omitempty"`
+	UseCount    int       `json:"use_count,omitempty"`
+	Tags        []string  `json:"tags,omitempty"`
+	Aliases     []string  `json:"aliases,omitempty"`
+	Notes       string    `json:"notes,omitempty"`
+	LoggedOut   bool      `json:"logged_out,omitempty"`
+	WranglerCmd string    `json:"wrangler_cmd,omitempty"` // pinned wrangler command, overrides the global one
+	Color       string    `json:"color,omitempty"`        // display color, one of Colors
+	Favorite    bool      `json:"favorite,omitempty"`     // listed before everything else
+	Protected   bool      `json:"protected,omitempty"`    // switching to it needs the name typed
+	RevertAfter int       `json:"revert_after,omitempty"` // minutes before switching back to the previous account
 }
 
+// Colors are the display colors an account can have
+var Colors = []string{"red", "green", "yellow", "blue", "magenta", "cyan", "white"}
+
 type Settings struct {
-	WranglerCmd     string    `json:"wrangler_cmd"`
-	LastUpdateCheck time.Time `json:"last_update_check,omitempty"`
+	WranglerCmd        string              `json:"wrangler_cmd"`            // shell-quoted command line
+	WranglerArgv       []string            `json:"wrangler_argv,omitempty"` // explicit argv, wins over WranglerCmd
+	WranglerEnv        map[string]string   `json:"wrangler_env,omitempty"`  // extra env for wrangler
+	Hooks              map[string][]string `json:"hooks,omitempty"`
+	UpdateChannel      string              `json:"update_channel,omitempty"`
+	DisableUpdateCheck bool                `json:"disable_update_check,omitempty"`
+}
+
+// UpdateState caches the result of the last update check
+type UpdateState struct {
+	LastCheck     time.Time `json:"last_check,omitempty"`   // last check that got an answer
+	LastAttempt   time.Time `json:"last_attempt,omitempty"` // last background check started
+	LastNotified  time.Time `json:"last_notified,omitempty"`
+	Channel       string    `json:"channel,omitempty"`
+	ETag          string    `json:"etag,omitempty"`
+	LatestVersion string    `json:"latest_version,omitempty"`
+}
+
+// WranglerState caches the last successful check of the wrangler command
+type WranglerState struct {
+	Cmd       string    `json:"cmd,omitempty"`
+	Path      string    `json:"path,omitempty"`
+	ModTime   time.Time `json:"mod_time,omitempty"`
+	Version   string    `json:"version,omitempty"`
+	CheckedAt time.Time `json:"checked_at,omitempty"`
+}
+
+// RevertState is a pending automatic switch back to the previous account
+type RevertState struct {
+	From string    `json:"from"` // account to switch away from
+	To   string    `json:"to"`   // account to switch back to
+	At   time.Time `json:"at"`
 }
 
 type AccountsDB struct {
-	Accounts []Account `json:"accounts"`
-	Current  string    `json:"current"`
-	Settings Settings  `json:"settings"`
+	Accounts []Account     `json:"accounts"`
+	Current  string        `json:"current"`
+	Previous string        `json:"previous,omitempty"`
+	Revert   *RevertState  `json:"revert,omitempty"`
+	Settings Settings      `json:"settings"`
+	Update   UpdateState   `json:"update"`
+	Wrangler WranglerState `json:"wrangler"`
 }
 
 // LoadDB loads the accounts database from disk
@@ -91,6 +143,9 @@ func (db *AccountsDB) RemoveAccount(id string) {
 			if db.Current == id {
 				db.Current = ""
 			}
+			if db.Previous == id {
+				db.Previous = ""
+			}
 			return
 		}
 	}
@@ -106,6 +161,149 @@ func (db *AccountsDB) GetAccount(id string) *Account {
 	return nil
 }
 
+// AccountForLogin returns the account to save for a wrangler login, keeping
+// the display name, history and metadata of an existing profile for the same account
+func (db *AccountsDB) AccountForLogin(id, name, email string) Account {
+	account := Account{ID: id, Name: name, AddedAt: time.Now()}
+	if existing := db.GetAccount(id); existing != nil {
+		account = *existing
+	}
+	account.AccountName = name
+	account.Email = email
+	account.LoggedOut = false
+	return account
+}
+
+// FindByAlias finds an account by one of its aliases
+func (db *AccountsDB) FindByAlias(alias string) *Account {
+	for _, a := range db.Accounts {
+		if a.HasAlias(alias) {
+			return &a
+		}
+	}
+	return nil
+}
+
+// HasAlias returns true if the account has the given alias, ignoring case
+// like display names
+func (a *Account) HasAlias(alias string) bool {
+	for _, existing := range a.Aliases {
+		if strings.EqualFold(existing, alias) {
+			return true
+		}
+	}
+	return false
+}
+
+// HasTag returns true if the account has the given tag
+func (a *Account) HasTag(tag string) bool {
+	for _, existing := range a.Tags {
+		if existing == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// HasAnyTag returns true if the account has one of tags, or tags is empty
+func (a *Account) HasAnyTag(tags []string) bool {
+	if len(tags) == 0 {
+		return true
+	}
+	for _, tag := range tags {
+		if a.HasTag(tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// FilterByTags returns the accounts that have one of tags, or all of them if tags is empty
+func FilterByTags(accounts []Account, tags []string) []Account {
+	var filtered []Account
+	for _, acc := range accounts {
+		if acc.HasAnyTag(tags) {
+			filtered = append(filtered, acc)
+		}
+	}
+	return filtered
+}
+
+// AccountGroup is a set of accounts shown under one heading
+type AccountGroup struct {
+	Name     string // FavoritesGroup, a tag, or empty for untagged accounts
+	Accounts []Account
+}
+
+// FavoritesGroup names the group of favorite accounts
+const FavoritesGroup = "Favorites"
+
+// GroupAccounts puts favorites first, then groups the other accounts by their
+// first tag in alphabetical order, with untagged accounts last. Accounts keep
+// their order within a group.
+func GroupAccounts(accounts []Account) []AccountGroup {
+	var favorites, untagged []Account
+	byTag := map[string][]Account{}
+	var tags []string
+	for _, acc := range accounts {
+		switch {
+		case acc.Favorite:
+			favorites = append(favorites, acc)
+		case len(acc.Tags) == 0:
+			untagged = append(untagged, acc)
+		default:
+			tag := acc.Tags[0]
+			if _, ok := byTag[tag]; !ok {
+				tags = append(tags, tag)
+			}
+			byTag[tag] = append(byTag[tag], acc)
+		}
+	}
+	sort.Strings(tags)
+
+	var groups []AccountGroup
+	if len(favorites) > 0 {
+		groups = append(groups, AccountGroup{Name: FavoritesGroup, Accounts: favorites})
+	}
+	for _, tag := range tags {
+		groups = append(groups, AccountGroup{Name: tag, Accounts: byTag[tag]})
+	}
+	if len(untagged) > 0 {
+		groups = append(groups, AccountGroup{Accounts: untagged})
+	}
+	return groups
+}
+
+// SetCurrent makes an account the current one, remembering the previous
+// account for 'cl switch -' and recording the use for MRU ordering.
+// Any pending revert is cancelled.
+func (db *AccountsDB) SetCurrent(id string) {
+	if db.Current != id {
+		db.Previous = db.Current
+	}
+	db.Revert = nil
+	db.Current = id
+
+	for i := range db.Accounts {
+		if db.Accounts[i].ID == id {
+			db.Accounts[i].LastUsedAt = time.Now()
+			db.Accounts[i].UseCount++
+			return
+		}
+	}
+}
+
+// AccountsByRecent returns the accounts ordered by most recently used first.
+// Accounts that were never used keep their saved order at the end.
+func (db *AccountsDB) AccountsByRecent() []Account {
+	accounts := make([]Account, len(db.Accounts))
+	copy(accounts, db.Accounts)
+	sort.SliceStable(accounts, func(i, j int) bool {
+		return accounts[i].LastUsedAt.After(accounts[j].LastUsedAt)
+	})
+	return accounts
+}
+
 // HashFile computes SHA256 hash of a file
 func HashFile(path string) (string, error) {
 	data, err := os.ReadFile(path)
@@ -116,6 +314,94 @@ func HashFile(path string) (string, error) {
 	return hex.EncodeToString(hash[:]), nil
 }
 
+// Token types found in wrangler configs
+const (
+	TokenTypeOAuth    = "oauth"
+	TokenTypeAPIToken = "api_token"
+	TokenTypeAPIKey   = "api_key" // global API key, used together with the account email
+)
+
+// TokenInfo describes the credentials stored in a wrangler config
+type TokenInfo struct {
+	Type         string    // TokenTypeOAuth, TokenTypeAPIToken, TokenTypeAPIKey or "" if unknown
+	Token        string    // OAuth access token, API token or API key
+	ExpiresAt    time.Time // zero if the token has no expiry
+	RefreshToken string    // OAuth refresh token, empty for API tokens
+}
+
+var tomlStringRegex = regexp.MustCompile(`(?m)^\s*(\w+)\s*=\s*"([^"]*)"`)
+
+// ReadTokenInfo parses the token type and expiry from a wrangler config file
+func ReadTokenInfo(path string) (*TokenInfo, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	info := &TokenInfo{}
+	for _, match := range tomlStringRegex.FindAllStringSubmatch(string(data), -1) {
+		switch match[1] {
+		case "oauth_token":
+			info.Type, info.Token = TokenTypeOAuth, match[2]
+		case "api_token":
+			info.Type, info.Token = TokenTypeAPIToken, match[2]
+		case "api_key":
+			info.Type, info.Token = TokenTypeAPIKey, match[2]
+		case "refresh_token":
+			info.RefreshToken = match[2]
+		case "expiration_time":
+			if t, err := time.Parse(time.RFC3339, match[2]); err == nil {
+				info.ExpiresAt = t
+			}
+		}
+	}
+	return info, nil
+}
+
+// oauthExpiryFormat is the format wrangler writes expiration_time in
+const oauthExpiryFormat = "2006-01-02T15:04:05.000Z"
+
+// UpdateOAuthToken replaces the OAuth tokens and expiry in a wrangler config
+func UpdateOAuthToken(path, accessToken, refreshToken string, expiresAt time.Time) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	values := map[string]string{
+		"oauth_token":     accessToken,
+		"refresh_token":   refreshToken,
+		"expiration_time": expiresAt.UTC().Format(oauthExpiryFormat),
+	}
+	for key, value := range values {
+		re := regexp.MustCompile(`(?m)^(\s*` + key + `\s*=\s*)"[^"]*"`)
+		data = re.ReplaceAllFunc(data, func(match []byte) []byte {
+			prefix := re.FindSubmatch(match)[1]
+			return append(append([]byte{}, prefix...), `"`+value+`"`...)
+		})
+	}
+	return os.WriteFile(path, data, 0600)
+}
+
+// AccountConfigPath returns the config file holding an account's credentials:
+// the live wrangler config for the current account, since it may hold a
+// refreshed token, and the saved copy otherwise
+func (db *AccountsDB) AccountConfigPath(accountID string) (string, error) {
+	if accountID == db.Current {
+		return config.GetWranglerConfigPath()
+	}
+	return config.GetAccountConfigPath(accountID)
+}
+
+// GetAccountTokenInfo returns token info for an account
+func (db *AccountsDB) GetAccountTokenInfo(accountID string) (*TokenInfo, error) {
+	path, err := db.AccountConfigPath(accountID)
+	if err != nil {
+		return nil, err
+	}
+	return ReadTokenInfo(path)
+}
+
 // GetCurrentConfigHash returns the hash of wrangler's current default.toml
 func GetCurrentConfigHash() (string, error) {
 	path, err := config.GetWranglerConfigPath()
@@ -0,0 +1,457 @@
+// Package tui implements the full-screen profile dashboard behind 'cl ui'.
+package tui
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/groo-dev/cl-wrangler/cli/internal/store"
+)
+
+// Actions are the profile operations the dashboard can trigger.
+// Switch, Login and Remove run with the terminal released, so they may
+// print, prompt and run hooks; Rename and Refresh must not write to the terminal.
+type Actions struct {
+	Load    func() (*store.AccountsDB, error)
+	Switch  func(id string) error
+	Login   func(id string) (*store.Account, error)
+	Remove  func(id string) error
+	Rename  func(id, name string) error
+	Refresh func() error
+}
+
+// Run shows the dashboard until the user quits
+func Run(actions Actions) error {
+	m, err := newModel(actions)
+	if err != nil {
+		return err
+	}
+	_, err = tea.NewProgram(m, tea.WithAltScreen()).Run()
+	return err
+}
+
+type mode int
+
+const (
+	modeBrowse mode = iota
+	modeRename
+	modeConfirmRemove
+)
+
+type keyMap struct {
+	Switch  key.Binding
+	Refresh key.Binding
+	Rename  key.Binding
+	Relogin key.Binding
+	Remove  key.Binding
+	Add     key.Binding
+}
+
+var keys = keyMap{
+	Switch:  key.NewBinding(key.WithKeys("enter", "s"), key.WithHelp("enter", "switch")),
+	Refresh: key.NewBinding(key.WithKeys("r"), key.WithHelp("r", "refresh")),
+	Rename:  key.NewBinding(key.WithKeys("n"), key.WithHelp("n", "rename")),
+	Relogin: key.NewBinding(key.WithKeys("l"), key.WithHelp("l", "re-login")),
+	Remove:  key.NewBinding(key.WithKeys("x", "delete"), key.WithHelp("x", "remove")),
+	Add:     key.NewBinding(key.WithKeys("a"), key.WithHelp("a", "add")),
+}
+
+func (k keyMap) bindings() []key.Binding {
+	return []key.Binding{k.Switch, k.Refresh, k.Rename, k.Relogin, k.Remove, k.Add}
+}
+
+var (
+	detailsStyle = lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(lipgloss.Color("62")).
+			Padding(0, 1)
+	titleStyle  = lipgloss.NewStyle().Bold(true)
+	labelStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("245")).Width(12)
+	warnStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("214"))
+	errorStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("196"))
+	okStyle     = lipgloss.NewStyle().Foreground(lipgloss.Color("42"))
+	statusStyle = lipgloss.NewStyle().Padding(0, 1)
+)
+
+// profile is a list item for one saved account
+type profile struct {
+	account store.Account
+	current bool
+	token   *store.TokenInfo
+}
+
+func (p profile) Title() string {
+	title := p.account.Name
+	if p.account.Favorite {
+		title = "★ " + title
+	}
+	if p.current {
+		title = "✓ " + title
+	}
+	if p.account.LoggedOut {
+		title += " [logged out]"
+	} else if p.expired() {
+		title += " [expired]"
+	}
+	return title
+}
+
+func (p profile) Description() string { return p.account.Email }
+
+// FilterValue lets the list search names, emails, IDs, aliases and tags
+func (p profile) FilterValue() string {
+	fields := []string{p.account.Name, p.account.Email, p.account.ID}
+	fields = append(fields, p.account.Aliases...)
+	fields = append(fields, p.account.Tags...)
+	return strings.Join(fields, " ")
+}
+
+func (p profile) expired() bool {
+	return p.token != nil && !p.token.ExpiresAt.IsZero() && time.Now().After(p.token.ExpiresAt)
+}
+
+// actionDoneMsg reports the outcome of an action
+type actionDoneMsg struct {
+	status string
+	err    error
+}
+
+// funcCommand runs a Go function as a tea.ExecCommand, releasing the terminal
+type funcCommand struct {
+	fn func() error
+}
+
+func (c *funcCommand) Run() error { return c.fn() }
+
+func (c *funcCommand) SetStdin(io.Reader)  {}
+func (c *funcCommand) SetStdout(io.Writer) {}
+func (c *funcCommand) SetStderr(io.Writer) {}
+
+type model struct {
+	actions Actions
+	list    list.Model
+	input   textinput.Model
+	mode    mode
+	status  string
+	err     error
+	width   int
+	height  int
+}
+
+func newModel(actions Actions) (model, error) {
+	l := list.New(nil, list.NewDefaultDelegate(), 0, 0)
+	l.Title = "Wrangler accounts"
+	l.SetStatusBarItemName("account", "accounts")
+	l.AdditionalShortHelpKeys = keys.bindings
+	l.AdditionalFullHelpKeys = keys.bindings
+	// "l" is re-login here, so page with the arrow keys only
+	l.KeyMap.NextPage = key.NewBinding(key.WithKeys("right", "pgdown", "f"), key.WithHelp("→/pgdn", "next page"))
+
+	input := textinput.New()
+	input.Prompt = "New name: "
+	input.CharLimit = 64
+
+	m := model{actions: actions, list: l, input: input}
+	if err := m.reload(); err != nil {
+		return m, err
+	}
+	return m, nil
+}
+
+// reload reads the accounts database and rebuilds the list, keeping the selection
+func (m *model) reload() error {
+	db, err := m.actions.Load()
+	if err != nil {
+		return err
+	}
+
+	selected := m.selected()
+	var items []list.Item
+	index := 0
+	var accounts []store.Account
+	for _, group := range store.GroupAccounts(db.AccountsByRecent()) {
+		accounts = append(accounts, group.Accounts...)
+	}
+	for _, acc := range accounts {
+		p := profile{account: acc, current: acc.ID == db.Current}
+		if info, err := db.GetAccountTokenInfo(acc.ID); err == nil {
+			p.token = info
+		}
+		if selected != nil && acc.ID == selected.account.ID {
+			index = len(items)
+		}
+		items = append(items, p)
+	}
+
+	m.list.SetItems(items)
+	m.list.Select(index)
+	return nil
+}
+
+func (m model) selected() *profile {
+	item, ok := m.list.SelectedItem().(profile)
+	if !ok {
+		return nil
+	}
+	return &item
+}
+
+func (m model) Init() tea.Cmd {
+	return nil
+}
+
+// run releases the terminal, runs fn and reports status when it succeeds
+func run(fn func() error, status string) tea.Cmd {
+	return tea.Exec(&funcCommand{fn: fn}, func(err error) tea.Msg {
+		return actionDoneMsg{status: status, err: err}
+	})
+}
+
+func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+		m.list.SetSize(m.listWidth(), msg.Height-1)
+		return m, nil
+
+	case actionDoneMsg:
+		m.status, m.err = msg.status, msg.err
+		if err := m.reload(); err != nil && m.err == nil {
+			m.err = err
+		}
+		return m, nil
+
+	case tea.KeyMsg:
+		switch m.mode {
+		case modeRename:
+			return m.updateRename(msg)
+		case modeConfirmRemove:
+			return m.updateConfirmRemove(msg)
+		}
+
+		// Let the filter input have every key while searching
+		if m.list.FilterState() == list.Filtering {
+			break
+		}
+		if cmd, ok := m.handleKey(msg); ok {
+			return m, cmd
+		}
+	}
+
+	var cmd tea.Cmd
+	m.list, cmd = m.list.Update(msg)
+	return m, cmd
+}
+
+// handleKey runs the dashboard bindings; ok is false for keys the list handles
+func (m *model) handleKey(msg tea.KeyMsg) (tea.Cmd, bool) {
+	if key.Matches(msg, keys.Add) {
+		return m.login(nil), true
+	}
+
+	p := m.selected()
+	if p == nil {
+		return nil, false
+	}
+
+	switch {
+	case key.Matches(msg, keys.Switch):
+		if p.account.LoggedOut {
+			return m.login(p), true
+		}
+		if p.current {
+			m.status, m.err = fmt.Sprintf("%s is already active", p.account.Name), nil
+			return nil, true
+		}
+		id := p.account.ID
+		return run(func() error { return m.actions.Switch(id) }, "Switched to "+p.account.Name), true
+
+	case key.Matches(msg, keys.Refresh):
+		m.status, m.err = "Refreshed", m.actions.Refresh()
+		if err := m.reload(); err != nil && m.err == nil {
+			m.err = err
+		}
+		return nil, true
+
+	case key.Matches(msg, keys.Rename):
+		m.mode = modeRename
+		m.input.SetValue(p.account.Name)
+		m.input.CursorEnd()
+		return m.input.Focus(), true
+
+	case key.Matches(msg, keys.Relogin):
+		return m.login(p), true
+
+	case key.Matches(msg, keys.Remove):
+		m.mode = modeConfirmRemove
+		return nil, true
+	}
+
+	return nil, false
+}
+
+// login runs wrangler login, checking the result against p when re-logging in
+func (m *model) login(p *profile) tea.Cmd {
+	var id string
+	if p != nil {
+		id = p.account.ID
+	}
+	var got *store.Account
+	fn := func() error {
+		var err error
+		got, err = m.actions.Login(id)
+		return err
+	}
+	return tea.Exec(&funcCommand{fn: fn}, func(err error) tea.Msg {
+		switch {
+		case err != nil:
+			return actionDoneMsg{err: err}
+		case p != nil && got.ID != p.account.ID:
+			return actionDoneMsg{err: fmt.Errorf("logged in as %s instead of %s", got.Name, p.account.Name)}
+		default:
+			return actionDoneMsg{status: "Logged in as " + got.Name}
+		}
+	})
+}
+
+func (m model) updateRename(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.mode = modeBrowse
+		m.input.Blur()
+		return m, nil
+	case tea.KeyEnter:
+		m.mode = modeBrowse
+		m.input.Blur()
+		p := m.selected()
+		name := strings.TrimSpace(m.input.Value())
+		if p == nil || name == "" || name == p.account.Name {
+			return m, nil
+		}
+		m.err = m.actions.Rename(p.account.ID, name)
+		m.status = fmt.Sprintf("Renamed %s → %s", p.account.Name, name)
+		if err := m.reload(); err != nil && m.err == nil {
+			m.err = err
+		}
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	return m, cmd
+}
+
+func (m model) updateConfirmRemove(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	m.mode = modeBrowse
+	p := m.selected()
+	if p == nil || (msg.String() != "y" && msg.String() != "Y") {
+		m.status, m.err = "Cancelled", nil
+		return m, nil
+	}
+
+	id := p.account.ID
+	return m, run(func() error { return m.actions.Remove(id) }, "Removed "+p.account.Name)
+}
+
+func (m model) listWidth() int {
+	return m.width / 2
+}
+
+func (m model) View() string {
+	details := detailsStyle.
+		Width(m.width - m.listWidth() - detailsStyle.GetHorizontalFrameSize()).
+		Height(m.height - 1 - detailsStyle.GetVerticalFrameSize()).
+		Render(m.details())
+
+	return lipgloss.JoinVertical(lipgloss.Left,
+		lipgloss.JoinHorizontal(lipgloss.Top, m.list.View(), details),
+		m.statusLine(),
+	)
+}
+
+func (m model) details() string {
+	p := m.selected()
+	if p == nil {
+		return "No accounts saved. Press a to add one."
+	}
+
+	acc := p.account
+	var b strings.Builder
+	b.WriteString(titleStyle.Render(acc.Name) + "\n\n")
+
+	row := func(label, value string) {
+		b.WriteString(labelStyle.Render(label) + value + "\n")
+	}
+	row("Email", acc.Email)
+	row("ID", acc.ID)
+	if acc.AccountName != "" && acc.AccountName != acc.Name {
+		row("Account", acc.AccountName)
+	}
+	row("Token", m.tokenStatus(p))
+	row("Last used", formatTime(acc.LastUsedAt))
+	row("Added", formatTime(acc.AddedAt))
+	row("Uses", fmt.Sprintf("%d", acc.UseCount))
+	row("Tags", joinOrNone(acc.Tags))
+	if acc.Color != "" {
+		row("Color", acc.Color)
+	}
+	row("Aliases", joinOrNone(acc.Aliases))
+	if acc.Notes != "" {
+		b.WriteString("\n" + acc.Notes + "\n")
+	}
+
+	return b.String()
+}
+
+func (m model) tokenStatus(p *profile) string {
+	switch {
+	case p.account.LoggedOut:
+		return warnStyle.Render("logged out")
+	case p.token == nil:
+		return "unknown"
+	case p.token.Type == store.TokenTypeAPIToken:
+		return "API token"
+	case p.token.ExpiresAt.IsZero():
+		return "OAuth"
+	case p.expired():
+		return warnStyle.Render("expired " + p.token.ExpiresAt.Local().Format("2006-01-02 15:04"))
+	default:
+		return "expires " + p.token.ExpiresAt.Local().Format("2006-01-02 15:04")
+	}
+}
+
+func (m model) statusLine() string {
+	switch {
+	case m.mode == modeRename:
+		return statusStyle.Render(m.input.View())
+	case m.mode == modeConfirmRemove:
+		p := m.selected()
+		return statusStyle.Render(warnStyle.Render(fmt.Sprintf("Remove %s (%s)? [y/N]", p.account.Name, p.account.Email)))
+	case m.err != nil:
+		return statusStyle.Render(errorStyle.Render("✗ " + m.err.Error()))
+	case m.status != "":
+		return statusStyle.Render(okStyle.Render("✓ " + m.status))
+	}
+	return ""
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return "never"
+	}
+	return t.Local().Format("2006-01-02 15:04")
+}
+
+func joinOrNone(values []string) string {
+	if len(values) == 0 {
+		return "none"
+	}
+	return strings.Join(values, ", ")
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"os"
 	"time"
 
 	"github.com/Masterminds/semver/v3"
@@ -13,52 +14,140 @@ const (
 	repoOwner     = "groo-dev"
 	repoName      = "cl-wrangler"
 	checkInterval = 24 * time.Hour
-	versionAPI    = "https://ops.groo.dev/v1/webhook/version?environment=production"
-	apiToken      = "groo_b310854d80189784e3ed222a5860562f992587bf8b1f34d6677d0c1857812461"
+	retryInterval = time.Hour
 )
 
-type versionResponse struct {
-	Version string `json:"version"`
+// Release channels
+const (
+	ChannelStable = "stable"
+	ChannelBeta   = "beta"
+)
+
+// releasesAPI is the public GitHub API for releases; no token is needed
+var releasesAPI = fmt.Sprintf("https://api.github.com/repos/%s/%s/releases", repoOwner, repoName)
+
+type release struct {
+	TagName    string `json:"tag_name"`
+	Draft      bool   `json:"draft"`
+	Prerelease bool   `json:"prerelease"`
+}
+
+// Result is the outcome of an update check
+type Result struct {
+	Version     string // latest version on the channel, empty if NotModified
+	ETag        string // ETag to send with the next check
+	NotModified bool   // the server confirmed the cached result is still current
+}
+
+// Enabled returns false if update checks are turned off by the setting,
+// CL_NO_UPDATE_CHECK, or because we're running in CI
+func Enabled(disabledBySetting bool) bool {
+	if disabledBySetting {
+		return false
+	}
+	if os.Getenv("CL_NO_UPDATE_CHECK") != "" {
+		return false
+	}
+	return os.Getenv("CI") == ""
+}
+
+// NormalizeChannel returns a valid channel, defaulting to stable
+func NormalizeChannel(channel string) string {
+	if channel == ChannelBeta {
+		return ChannelBeta
+	}
+	return ChannelStable
 }
 
-// CheckForUpdate checks for a newer version
-// Returns (newVersion, downloadURL, error)
-func CheckForUpdate(currentVersion string) (string, string, error) {
+// Fetch checks the latest version on a channel.
+// If etag is set and the release list hasn't changed, the result is NotModified.
+func Fetch(channel, etag string) (*Result, error) {
 	client := &http.Client{Timeout: 5 * time.Second}
 
-	req, err := http.NewRequest("GET", versionAPI, nil)
+	url := releasesAPI + "/latest"
+	if channel == ChannelBeta {
+		url = releasesAPI + "?per_page=20"
+	}
+
+	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
-		return "", "", err
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
 	}
-	req.Header.Set("Authorization", "Bearer "+apiToken)
 
 	resp, err := client.Do(req)
 	if err != nil {
-		return "", "", err
+		return nil, err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != 200 {
-		return "", "", fmt.Errorf("failed to fetch version: status %d", resp.StatusCode)
+	if resp.StatusCode == http.StatusNotModified {
+		return &Result{ETag: etag, NotModified: true}, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch version: status %d", resp.StatusCode)
+	}
+
+	var releases []release
+	if channel == ChannelBeta {
+		if err := json.NewDecoder(resp.Body).Decode(&releases); err != nil {
+			return nil, err
+		}
+	} else {
+		var latest release
+		if err := json.NewDecoder(resp.Body).Decode(&latest); err != nil {
+			return nil, err
+		}
+		releases = []release{latest}
 	}
 
-	var versionResp versionResponse
-	if err := json.NewDecoder(resp.Body).Decode(&versionResp); err != nil {
-		return "", "", err
+	version := newestVersion(releases)
+	if version == "" {
+		return nil, fmt.Errorf("no releases found")
 	}
 
-	latestVersion := versionResp.Version
+	return &Result{Version: version, ETag: resp.Header.Get("ETag")}, nil
+}
 
-	if isNewerVersion(latestVersion, currentVersion) {
-		releaseURL := fmt.Sprintf("https://github.com/%s/%s/releases/tag/v%s", repoOwner, repoName, latestVersion)
-		return latestVersion, releaseURL, nil
+// LatestVersion returns the latest released version on a channel
+func LatestVersion(channel string) (string, error) {
+	result, err := Fetch(channel, "")
+	if err != nil {
+		return "", err
 	}
+	return result.Version, nil
+}
 
-	return "", "", nil
+// ReleasePageURL returns the release page for a version
+func ReleasePageURL(version string) string {
+	return fmt.Sprintf("https://github.com/%s/%s/releases/tag/v%s", repoOwner, repoName, version)
 }
 
-// isNewerVersion compares semantic versions using proper semver parsing
-func isNewerVersion(latest, current string) bool {
+func newestVersion(releases []release) string {
+	var newest *semver.Version
+	for _, r := range releases {
+		if r.Draft {
+			continue
+		}
+		v, err := semver.NewVersion(r.TagName)
+		if err != nil {
+			continue
+		}
+		if newest == nil || v.GreaterThan(newest) {
+			newest = v
+		}
+	}
+	if newest == nil {
+		return ""
+	}
+	return newest.String()
+}
+
+// IsNewerVersion compares semantic versions using proper semver parsing
+func IsNewerVersion(latest, current string) bool {
 	// Skip check for dev versions
 	if current == "dev" || current == "" {
 		return false
@@ -81,3 +170,9 @@ func isNewerVersion(latest, current string) bool {
 func ShouldCheck(lastCheck time.Time) bool {
 	return time.Since(lastCheck) > checkInterval
 }
+
+// ShouldAttempt returns true if a check is due and none was started
+// recently, so a failing check isn't retried on every command
+func ShouldAttempt(lastCheck, lastAttempt time.Time) bool {
+	return ShouldCheck(lastCheck) && time.Since(lastAttempt) > retryInterval
+}
@@ -0,0 +1,291 @@
+package update
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// DefaultReleaseURL is where goreleaser publishes release assets
+var DefaultReleaseURL = fmt.Sprintf("https://github.com/%s/%s/releases", repoOwner, repoName)
+
+// releasePublicKey is the base64 ed25519 key that signs checksums.txt,
+// embedded at build time with -ldflags "-X ...update.releasePublicKey=<key>".
+// Builds with a key require every release to be signed with it.
+var releasePublicKey = ""
+
+// ErrNotFound is returned when a release asset doesn't exist
+var ErrNotFound = errors.New("not found")
+
+// InstallMethod describes how the running binary was installed
+type InstallMethod string
+
+const (
+	InstallBinary   InstallMethod = "binary"
+	InstallHomebrew InstallMethod = "homebrew"
+	InstallNpm      InstallMethod = "npm"
+	InstallPip      InstallMethod = "pip"
+)
+
+// ReleaseURL returns the release base URL, overridable with CL_RELEASE_URL
+func ReleaseURL() string {
+	if url := os.Getenv("CL_RELEASE_URL"); url != "" {
+		return strings.TrimSuffix(url, "/")
+	}
+	return DefaultReleaseURL
+}
+
+// ReleasePublicKey returns the key used to verify checksum signatures.
+// Only builds without an embedded key (e.g. 'go build') read CL_RELEASE_PUBKEY,
+// so the environment can't replace the key of a release build.
+func ReleasePublicKey() string {
+	if releasePublicKey != "" {
+		return releasePublicKey
+	}
+	return os.Getenv("CL_RELEASE_PUBKEY")
+}
+
+// ArchiveName returns the goreleaser archive name for a platform
+func ArchiveName(goos, goarch string) string {
+	if goos == "windows" {
+		return fmt.Sprintf("cl_%s_%s.zip", goos, goarch)
+	}
+	return fmt.Sprintf("cl_%s_%s.tar.gz", goos, goarch)
+}
+
+// DetectInstallMethod guesses the install method from the executable path
+func DetectInstallMethod(exePath string) InstallMethod {
+	path := filepath.ToSlash(exePath)
+	switch {
+	case strings.Contains(path, "/Cellar/") || strings.Contains(path, "/homebrew/") || strings.Contains(path, "/linuxbrew/"):
+		return InstallHomebrew
+	case strings.Contains(path, "/node_modules/"):
+		return InstallNpm
+	case strings.Contains(path, "/.cache/cl-wrangler/") || strings.Contains(path, "/site-packages/"):
+		return InstallPip
+	}
+	return InstallBinary
+}
+
+// UpgradeCommand returns the package manager command that upgrades cl,
+// or nil for a standalone binary
+func (m InstallMethod) UpgradeCommand() []string {
+	switch m {
+	case InstallHomebrew:
+		return []string{"brew", "upgrade", "groo-dev/tap/cl"}
+	case InstallNpm:
+		return []string{"npm", "install", "-g", "@groo.dev/cl-wrangler@latest"}
+	case InstallPip:
+		return []string{"pip", "install", "--upgrade", "cl-wrangler"}
+	}
+	return nil
+}
+
+// DownloadRelease downloads the archive for the running platform, verifies it
+// against the release's checksums.txt (and signature, when available) and
+// returns the extracted cl binary. It reports whether a signature was verified.
+func DownloadRelease(baseURL, version string) ([]byte, bool, error) {
+	client := &http.Client{Timeout: 2 * time.Minute}
+	releaseURL := fmt.Sprintf("%s/download/v%s", baseURL, strings.TrimPrefix(version, "v"))
+	archive := ArchiveName(runtime.GOOS, runtime.GOARCH)
+
+	checksums, err := fetch(client, releaseURL+"/checksums.txt")
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to download checksums.txt: %w", err)
+	}
+
+	signed, err := verifySignature(client, releaseURL, checksums)
+	if err != nil {
+		return nil, false, err
+	}
+
+	expected, err := findChecksum(checksums, archive)
+	if err != nil {
+		return nil, false, err
+	}
+
+	data, err := fetch(client, releaseURL+"/"+archive)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to download %s: %w", archive, err)
+	}
+
+	sum := sha256.Sum256(data)
+	if actual := hex.EncodeToString(sum[:]); actual != expected {
+		return nil, false, fmt.Errorf("checksum mismatch for %s: expected %s, got %s", archive, expected, actual)
+	}
+
+	binary, err := extractBinary(archive, data)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to extract %s: %w", archive, err)
+	}
+
+	return binary, signed, nil
+}
+
+// ReplaceExecutable atomically replaces the binary at path with data
+func ReplaceExecutable(path string, data []byte) error {
+	dir := filepath.Dir(path)
+	tmp, err := os.CreateTemp(dir, ".cl-upgrade-*")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath)
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmpPath, 0755); err != nil {
+		return err
+	}
+
+	// Windows can't overwrite a running executable, but it can rename it
+	if runtime.GOOS == "windows" {
+		oldPath := path + ".old"
+		os.Remove(oldPath)
+		if err := os.Rename(path, oldPath); err != nil {
+			return err
+		}
+		if err := os.Rename(tmpPath, path); err != nil {
+			// Put the running binary back rather than leave cl missing
+			os.Rename(oldPath, path)
+			return err
+		}
+		return nil
+	}
+
+	return os.Rename(tmpPath, path)
+}
+
+func fetch(client *http.Client, url string) ([]byte, error) {
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("status %d", resp.StatusCode)
+	}
+
+	return io.ReadAll(resp.Body)
+}
+
+// verifySignature checks checksums.txt.sig. The signature is required when
+// a key is known, and a signature that can't be verified is always an error.
+func verifySignature(client *http.Client, releaseURL string, checksums []byte) (bool, error) {
+	key := ReleasePublicKey()
+
+	sig, err := fetch(client, releaseURL+"/checksums.txt.sig")
+	if errors.Is(err, ErrNotFound) {
+		if key != "" {
+			return false, fmt.Errorf("release has no checksums.txt.sig, but this build requires signed releases")
+		}
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to download checksums.txt.sig: %w", err)
+	}
+
+	if key == "" {
+		return false, fmt.Errorf("release is signed, but this build has no public key to verify checksums.txt.sig; set CL_RELEASE_PUBKEY")
+	}
+
+	pub, err := base64.StdEncoding.DecodeString(key)
+	if err != nil || len(pub) != ed25519.PublicKeySize {
+		return false, fmt.Errorf("invalid release public key")
+	}
+	sigBytes, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(sig)))
+	if err != nil {
+		return false, fmt.Errorf("invalid checksums.txt.sig: %w", err)
+	}
+	if !ed25519.Verify(pub, checksums, sigBytes) {
+		return false, fmt.Errorf("signature verification of checksums.txt failed")
+	}
+
+	return true, nil
+}
+
+// findChecksum returns the sha256 for a file from goreleaser's checksums.txt
+func findChecksum(checksums []byte, name string) (string, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(checksums))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && strings.TrimPrefix(fields[1], "*") == name {
+			return strings.ToLower(fields[0]), nil
+		}
+	}
+	return "", fmt.Errorf("no checksum for %s in checksums.txt", name)
+}
+
+func extractBinary(archive string, data []byte) ([]byte, error) {
+	if strings.HasSuffix(archive, ".zip") {
+		return extractZip(data, "cl.exe")
+	}
+	return extractTarGz(data, "cl")
+}
+
+func extractTarGz(data []byte, name string) ([]byte, error) {
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if header.Typeflag == tar.TypeReg && filepath.Base(header.Name) == name {
+			return io.ReadAll(tr)
+		}
+	}
+	return nil, fmt.Errorf("%s not found in archive", name)
+}
+
+func extractZip(data []byte, name string) ([]byte, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, f := range zr.File {
+		if filepath.Base(f.Name) != name {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, err
+		}
+		defer rc.Close()
+		return io.ReadAll(rc)
+	}
+	return nil, fmt.Errorf("%s not found in archive", name)
+}
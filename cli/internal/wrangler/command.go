@@ -0,0 +1,193 @@
+package wrangler
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/groo-dev/cl-wrangler/cli/internal/store"
+	"github.com/mattn/go-shellwords"
+)
+
+// Cmd is a resolved way of running wrangler: the program and leading
+// arguments, plus extra environment variables
+type Cmd struct {
+	Argv []string
+	Env  []string
+}
+
+// ParseCmd splits a command line with shell-words rules, so paths with
+// spaces can be quoted. Variables and backticks are not expanded. On Windows
+// backslashes are path separators, not escapes.
+func ParseCmd(line string) (Cmd, error) {
+	split := shellwords.Parse
+	if runtime.GOOS == "windows" {
+		split = splitWindowsCmdLine
+	}
+	argv, err := split(line)
+	if err != nil {
+		return Cmd{}, fmt.Errorf("invalid wrangler command %q: %w", line, err)
+	}
+	if len(argv) == 0 {
+		return Cmd{}, fmt.Errorf("empty wrangler command")
+	}
+	return Cmd{Argv: argv}, nil
+}
+
+// ConfiguredCmd returns the wrangler command from settings. An explicit
+// wrangler_argv wins over the shell-quoted wrangler_cmd.
+func ConfiguredCmd(settings store.Settings) (Cmd, error) {
+	var cmd Cmd
+	if len(settings.WranglerArgv) > 0 {
+		cmd.Argv = append([]string{}, settings.WranglerArgv...)
+	} else if settings.WranglerCmd != "" {
+		parsed, err := ParseCmd(settings.WranglerCmd)
+		if err != nil {
+			return Cmd{}, err
+		}
+		cmd = parsed
+	} else {
+		return Cmd{}, nil
+	}
+
+	cmd.Env = envList(settings.WranglerEnv)
+	return cmd, nil
+}
+
+// envList turns an env map into sorted KEY=VALUE pairs
+func envList(env map[string]string) []string {
+	var list []string
+	for key, value := range env {
+		list = append(list, key+"="+value)
+	}
+	sort.Strings(list)
+	return list
+}
+
+// IsZero reports whether no command is set
+func (c Cmd) IsZero() bool {
+	return len(c.Argv) == 0
+}
+
+// String returns the command as a shell-quoted line that ParseCmd reads back.
+// Environment variables are not included.
+func (c Cmd) String() string {
+	quoted := make([]string, len(c.Argv))
+	for i, arg := range c.Argv {
+		quoted[i] = shellQuote(arg)
+	}
+	return strings.Join(quoted, " ")
+}
+
+// key identifies the command and its environment for the check cache
+func (c Cmd) key() string {
+	return strings.Join(append([]string{c.String()}, c.Env...), " ")
+}
+
+// command builds the invocation of a wrangler subcommand
+func (c Cmd) command(args ...string) Command {
+	if c.IsZero() {
+		return Command{Args: args}
+	}
+	name := c.Argv[0]
+	if self != "" {
+		// Resolve now so exec doesn't find cl's own 'wrangler' symlink
+		if path, err := lookPath(name); err == nil {
+			name = path
+		}
+	}
+	return Command{
+		Name: name,
+		Args: append(c.Argv[1:len(c.Argv):len(c.Argv)], args...),
+		Env:  c.Env,
+	}
+}
+
+// shellQuote quotes arg for a POSIX shell if it needs it
+func shellQuote(arg string) string {
+	if runtime.GOOS == "windows" {
+		return windowsQuote(arg)
+	}
+	if arg == "" {
+		return "''"
+	}
+	if !strings.ContainsAny(arg, " \t\n'\"\\$`|&;<>()*?[]#~!{}") {
+		return arg
+	}
+	return "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+}
+
+// splitWindowsCmdLine splits a command line at spaces outside double or
+// single quotes, keeping backslashes as they are, e.g. C:\tools\wrangler.cmd
+func splitWindowsCmdLine(line string) ([]string, error) {
+	var argv []string
+	var arg strings.Builder
+	var quote rune
+	inArg := false
+	for _, r := range line {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				arg.WriteRune(r)
+			}
+		case r == '"' || r == '\'':
+			quote = r
+			inArg = true
+		case r == ' ' || r == '\t' || r == '\n' || r == '\r':
+			if inArg {
+				argv = append(argv, arg.String())
+				arg.Reset()
+				inArg = false
+			}
+		default:
+			arg.WriteRune(r)
+			inArg = true
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated %c quote", quote)
+	}
+	if inArg {
+		argv = append(argv, arg.String())
+	}
+	return argv, nil
+}
+
+// windowsQuote quotes an argument for splitWindowsCmdLine; Windows paths
+// can't contain double quotes
+func windowsQuote(arg string) string {
+	if arg == "" {
+		return `""`
+	}
+	if !strings.ContainsAny(arg, " \t\n\r'\"") {
+		return arg
+	}
+	if strings.Contains(arg, `"`) {
+		return "'" + arg + "'"
+	}
+	return `"` + arg + `"`
+}
+
+// ValidateCmdLine checks that a command line parses and its program exists.
+// An unquoted path with spaces that names an existing file is accepted as is
+// and returned quoted.
+func ValidateCmdLine(line string) (string, error) {
+	line = strings.TrimSpace(line)
+	if _, err := os.Stat(line); err == nil && strings.ContainsAny(line, " \t") {
+		return shellQuote(line), nil
+	}
+
+	cmd, err := ParseCmd(line)
+	if err != nil {
+		return "", err
+	}
+	if _, err := exec.LookPath(cmd.Argv[0]); err != nil {
+		return "", fmt.Errorf("%s not found (quote paths that contain spaces)", cmd.Argv[0])
+	}
+	return line, nil
+}
@@ -0,0 +1,40 @@
+package wrangler
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSplitWindowsCmdLine(t *testing.T) {
+	for _, tt := range []struct {
+		line string
+		want []string
+	}{
+		{`C:\tools\wrangler.cmd`, []string{`C:\tools\wrangler.cmd`}},
+		{`"C:\Program Files\nodejs\npx.cmd" wrangler`, []string{`C:\Program Files\nodejs\npx.cmd`, "wrangler"}},
+		{`'C:\My Tools\wrangler.cmd'  --x`, []string{`C:\My Tools\wrangler.cmd`, "--x"}},
+		{`npx ""`, []string{"npx", ""}},
+	} {
+		got, err := splitWindowsCmdLine(tt.line)
+		if err != nil {
+			t.Errorf("splitWindowsCmdLine(%q): %v", tt.line, err)
+			continue
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("splitWindowsCmdLine(%q) = %q, want %q", tt.line, got, tt.want)
+		}
+	}
+
+	if _, err := splitWindowsCmdLine(`"C:\tools\wrangler.cmd`); err == nil {
+		t.Error("expected an error for an unterminated quote")
+	}
+}
+
+func TestWindowsQuoteRoundTrip(t *testing.T) {
+	for _, arg := range []string{`C:\tools\wrangler.cmd`, `C:\Program Files\wrangler.cmd`, `it's`, `say "hi"`, ""} {
+		got, err := splitWindowsCmdLine(windowsQuote(arg))
+		if err != nil || len(got) != 1 || got[0] != arg {
+			t.Errorf("round trip of %q = %q, %v", arg, got, err)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package wrangler
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// fakeResponse is the canned result of a faked command
+type fakeResponse struct {
+	Output string
+	Err    error
+}
+
+// fakeRunner records commands instead of running them and answers with
+// canned responses keyed by the full command line, e.g. "wrangler whoami"
+type fakeRunner struct {
+	Responses map[string]fakeResponse
+	Calls     []Command
+}
+
+func (f *fakeRunner) Run(ctx context.Context, c Command) ([]byte, error) {
+	f.Calls = append(f.Calls, c)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	line := strings.Join(append([]string{c.Name}, c.Args...), " ")
+	resp, ok := f.Responses[line]
+	if !ok {
+		return nil, fmt.Errorf("fake runner: no response for %s", line)
+	}
+	return []byte(resp.Output), resp.Err
+}
+
+// useFakeRunner installs a fakeRunner as DefaultRunner for the rest of the test
+func useFakeRunner(t *testing.T, responses map[string]fakeResponse) *fakeRunner {
+	t.Helper()
+	fake := &fakeRunner{Responses: responses}
+	previous := DefaultRunner
+	DefaultRunner = fake
+	t.Cleanup(func() { DefaultRunner = previous })
+	return fake
+}
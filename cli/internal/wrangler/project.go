@@ -0,0 +1,119 @@
+package wrangler
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// packageManager runs a project's pinned wrangler
+type packageManager struct {
+	lockfiles []string
+	argv      []string
+}
+
+// packageManagers are tried in this order when no lockfile decides
+var packageManagers = []packageManager{
+	{lockfiles: []string{"package-lock.json", "npm-shrinkwrap.json"}, argv: []string{"npx", "wrangler"}},
+	{lockfiles: []string{"pnpm-lock.yaml"}, argv: []string{"pnpm", "exec", "wrangler"}},
+	{lockfiles: []string{"bun.lock", "bun.lockb"}, argv: []string{"bunx", "wrangler"}},
+	{lockfiles: []string{"yarn.lock"}, argv: []string{"yarn", "wrangler"}},
+}
+
+// ProjectCmd finds the wrangler pinned by the project containing dir.
+// Walking up from dir, node_modules/.bin/wrangler wins; a package.json that
+// depends on wrangler without it (Yarn PnP, not yet installed) is run
+// through the package manager its lockfile names.
+func ProjectCmd(dir string) (Cmd, bool) {
+	for {
+		if bin, ok := findExecutable(filepath.Join(dir, "node_modules", ".bin", "wrangler")); ok {
+			return Cmd{Argv: []string{bin}}, true
+		}
+
+		if dependsOnWrangler(filepath.Join(dir, "package.json")) {
+			for _, pm := range packageManagersFor(dir) {
+				if _, err := lookPath(pm.argv[0]); err == nil {
+					return Cmd{Argv: pm.argv}, true
+				}
+			}
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return Cmd{}, false
+		}
+		dir = parent
+	}
+}
+
+// packageManagersFor orders the package managers with the one whose
+// lockfile is in dir first
+func packageManagersFor(dir string) []packageManager {
+	for i, pm := range packageManagers {
+		for _, lockfile := range pm.lockfiles {
+			if _, err := os.Stat(filepath.Join(dir, lockfile)); err == nil {
+				ordered := []packageManager{pm}
+				ordered = append(ordered, packageManagers[:i]...)
+				return append(ordered, packageManagers[i+1:]...)
+			}
+		}
+	}
+	return packageManagers
+}
+
+// dependsOnWrangler reports whether a package.json lists wrangler
+func dependsOnWrangler(path string) bool {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return false
+	}
+
+	var pkg struct {
+		Dependencies    map[string]string `json:"dependencies"`
+		DevDependencies map[string]string `json:"devDependencies"`
+	}
+	if err := json.Unmarshal(data, &pkg); err != nil {
+		return false
+	}
+	_, dep := pkg.Dependencies["wrangler"]
+	_, devDep := pkg.DevDependencies["wrangler"]
+	return dep || devDep
+}
+
+// findExecutable returns the executable file at path. On Windows, where
+// npm installs wrangler.cmd next to a shell script, it tries path with each
+// extension in PATHEXT instead.
+func findExecutable(path string) (string, bool) {
+	if runtime.GOOS != "windows" {
+		stat, err := os.Stat(path)
+		return path, err == nil && !stat.IsDir() && stat.Mode()&0111 != 0
+	}
+
+	for _, ext := range pathExts() {
+		if stat, err := os.Stat(path + ext); err == nil && !stat.IsDir() {
+			return path + ext, true
+		}
+	}
+	return "", false
+}
+
+// pathExts returns the executable extensions from PATHEXT, lowercased
+func pathExts() []string {
+	pathext := os.Getenv("PATHEXT")
+	if pathext == "" {
+		pathext = ".com;.exe;.bat;.cmd"
+	}
+	var exts []string
+	for _, ext := range strings.Split(strings.ToLower(pathext), ";") {
+		if ext == "" {
+			continue
+		}
+		if !strings.HasPrefix(ext, ".") {
+			ext = "." + ext
+		}
+		exts = append(exts, ext)
+	}
+	return exts
+}
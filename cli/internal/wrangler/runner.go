@@ -0,0 +1,109 @@
+package wrangler
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"os/exec"
+	"os/signal"
+	"regexp"
+	"syscall"
+	"time"
+)
+
+// killDelay is how long a cancelled command gets to exit after SIGINT
+// before it is killed
+const killDelay = 5 * time.Second
+
+// Command is one invocation of an external program
+type Command struct {
+	Name string
+	Args []string
+	// Env is added to cl's own environment
+	Env []string
+	// Interactive attaches the terminal instead of capturing output
+	Interactive bool
+}
+
+// Runner runs external commands. Captured output is returned with
+// credentials redacted.
+type Runner interface {
+	Run(ctx context.Context, cmd Command) ([]byte, error)
+}
+
+// DefaultRunner runs every wrangler command; tests replace it to exercise
+// this package without wrangler installed
+var DefaultRunner Runner = ExecRunner{}
+
+// ExecRunner runs commands as child processes. SIGINT and SIGTERM received
+// by cl don't stop it, so it can clean up once the child has exited; they
+// are forwarded to the child, except SIGINT for interactive commands, which
+// get it from the terminal. Cancelling the context interrupts the child,
+// then kills it.
+type ExecRunner struct{}
+
+func (ExecRunner) Run(ctx context.Context, c Command) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, c.Name, c.Args...)
+	cmd.Env = append(os.Environ(), c.Env...)
+	cmd.Cancel = func() error {
+		return cmd.Process.Signal(os.Interrupt)
+	}
+	cmd.WaitDelay = killDelay
+
+	var output bytes.Buffer
+	if c.Interactive {
+		cmd.Stdin = os.Stdin
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+	} else {
+		cmd.Stdout = &output
+		cmd.Stderr = &output
+	}
+
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(signals)
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		for {
+			select {
+			case sig := <-signals:
+				// A terminal's Ctrl-C already reaches an interactive child
+				// through the foreground process group; cl only outlives it
+				if c.Interactive && sig == syscall.SIGINT {
+					continue
+				}
+				cmd.Process.Signal(sig)
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	err := cmd.Wait()
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		err = ctxErr
+	}
+	return Redact(output.Bytes()), err
+}
+
+// secretPatterns match credentials wrangler may print, keeping the prefix
+var secretPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`(?i)((?:oauth_token|refresh_token|api_token|access_token)\s*[=:]\s*"?)[^"\s]+`),
+	regexp.MustCompile(`(?i)((?:CLOUDFLARE_API_TOKEN|CLOUDFLARE_API_KEY|CF_API_TOKEN|CF_API_KEY)=)\S+`),
+	regexp.MustCompile(`(?i)(Bearer\s+)[A-Za-z0-9._~+/=-]+`),
+}
+
+// Redact replaces tokens in command output with [REDACTED]
+func Redact(output []byte) []byte {
+	for _, re := range secretPatterns {
+		output = re.ReplaceAll(output, []byte("${1}[REDACTED]"))
+	}
+	return output
+}
@@ -0,0 +1,60 @@
+package wrangler
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+)
+
+// WrappedEnv is set while cl runs wrangler as its wrapper, so a wrangler
+// command that leads back to cl can fail instead of looping
+const WrappedEnv = "CL_WRANGLER_WRAPPED"
+
+// self is cl's own resolved executable once AvoidSelf has been called
+var self string
+
+// AvoidSelf makes wrangler lookups on PATH skip cl's own executable, for
+// when cl is installed as a 'wrangler' symlink in front of the real one
+func AvoidSelf() error {
+	exe, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	if resolved, err := filepath.EvalSymlinks(exe); err == nil {
+		exe = resolved
+	}
+	self = exe
+	return nil
+}
+
+// lookPath is exec.LookPath, skipping cl itself after AvoidSelf
+func lookPath(name string) (string, error) {
+	if self == "" || filepath.Base(name) != name {
+		return exec.LookPath(name)
+	}
+
+	for _, dir := range filepath.SplitList(os.Getenv("PATH")) {
+		if dir == "" {
+			continue
+		}
+		path, err := exec.LookPath(filepath.Join(dir, name))
+		if err != nil {
+			continue
+		}
+		if resolved, err := filepath.EvalSymlinks(path); err == nil && resolved == self {
+			continue
+		}
+		return path, nil
+	}
+	return "", fmt.Errorf("%s: no executable found in PATH other than cl itself", name)
+}
+
+// RealCmd returns the first wrangler on PATH that isn't cl itself
+func RealCmd() (Cmd, error) {
+	path, err := lookPath("wrangler")
+	if err != nil {
+		return Cmd{}, err
+	}
+	return Cmd{Argv: []string{path}}, nil
+}
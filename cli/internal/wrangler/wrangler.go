@@ -1,123 +1,335 @@
 package wrangler
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"net/http"
+	"net/url"
 	"os"
-	"os/exec"
+	"path/filepath"
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/huh"
+	"github.com/groo-dev/cl-wrangler/cli/internal/prompt"
 	"github.com/groo-dev/cl-wrangler/cli/internal/store"
 )
 
+const (
+	// oauthClientID is the OAuth client wrangler logs in with
+	oauthClientID = "54d11594-84e4-41aa-b438-e81b8fa78ee7"
+	revokeURL     = "https://dash.cloudflare.com/oauth2/revoke"
+	tokenURL      = "https://dash.cloudflare.com/oauth2/token"
+)
+
 type WhoamiInfo struct {
 	Email       string
 	AccountID   string
 	AccountName string
+	AccountIDs  []string // every account the login can access
 }
 
+const (
+	// versionTimeout bounds 'wrangler --version', which npx may need to download
+	versionTimeout = 30 * time.Second
+	// commandTimeout bounds non-interactive commands like whoami and logout
+	commandTimeout = time.Minute
+)
+
+// validationTTL is how long a successful wrangler check is trusted
+// while the resolved binary is unchanged
+const validationTTL = 24 * time.Hour
+
+var versionRegex = regexp.MustCompile(`\d+\.\d+\.\d+[0-9A-Za-z.+-]*`)
+
 // DetectWrangler tries to find wrangler and returns the command to use
-// It checks: wrangler, npx wrangler
+// It checks: wrangler, then npx, pnpm exec, bunx and yarn, starting with
+// the package manager whose lockfile is in the current directory
 // If not found, prompts user
-func DetectWrangler() (string, error) {
-	// Try direct wrangler first
-	if tryWranglerCmd("wrangler") {
-		return "wrangler", nil
+func DetectWrangler() (Cmd, error) {
+	cmd, _, err := detectWrangler()
+	return cmd, err
+}
+
+// detectWrangler returns the command to use and the version it reported
+func detectWrangler() (Cmd, string, error) {
+	candidates := []Cmd{{Argv: []string{"wrangler"}}}
+	cwd, _ := os.Getwd()
+	for _, pm := range packageManagersFor(cwd) {
+		if _, err := lookPath(pm.argv[0]); err == nil {
+			candidates = append(candidates, Cmd{Argv: pm.argv})
+		}
 	}
 
-	// Try npx wrangler
-	if tryWranglerCmd("npx wrangler") {
-		return "npx wrangler", nil
+	for _, cmd := range candidates {
+		if version, err := checkWranglerCmd(cmd); err == nil {
+			return cmd, version, nil
+		}
 	}
 
 	// Not found - ask user
-	return promptForWrangler()
+	cmd, err := promptForWrangler()
+	return cmd, "", err
 }
 
-func tryWranglerCmd(cmd string) bool {
-	parts := strings.Fields(cmd)
-	args := append(parts[1:], "--version")
+// checkWranglerCmd runs the command with --version and returns the reported version
+func checkWranglerCmd(cmd Cmd) (string, error) {
+	if cmd.IsZero() {
+		return "", fmt.Errorf("empty wrangler command")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), versionTimeout)
+	defer cancel()
 
-	c := exec.Command(parts[0], args...)
-	err := c.Run()
-	return err == nil
+	output, err := DefaultRunner.Run(ctx, cmd.command("--version"))
+	if err != nil {
+		return "", err
+	}
+	return versionRegex.FindString(string(output)), nil
+}
+
+// resolveBinary returns the resolved path and modification time of the
+// executable a wrangler command starts
+func resolveBinary(cmd Cmd) (string, time.Time, error) {
+	if cmd.IsZero() {
+		return "", time.Time{}, fmt.Errorf("empty wrangler command")
+	}
+
+	path, err := lookPath(cmd.Argv[0])
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		path = resolved
+	}
+
+	stat, err := os.Stat(path)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return path, stat.ModTime(), nil
 }
 
-func promptForWrangler() (string, error) {
+func promptForWrangler() (Cmd, error) {
+	if err := prompt.Require("set it with 'cl config --wrangler-cmd <command>'"); err != nil {
+		return Cmd{}, fmt.Errorf("wrangler not found: %w", err)
+	}
+
 	var choice string
 
 	err := huh.NewSelect[string]().
 		Title("Wrangler not found. How would you like to run wrangler?").
 		Options(
 			huh.NewOption("Use npx (downloads wrangler on demand)", "npx"),
-			huh.NewOption("Enter custom path", "custom"),
+			huh.NewOption("Enter custom command", "custom"),
 		).
 		Value(&choice).
 		Run()
 
 	if err != nil {
-		return "", err
+		return Cmd{}, err
 	}
 
 	if choice == "npx" {
-		return "npx wrangler", nil
+		return Cmd{Argv: []string{"npx", "wrangler"}}, nil
 	}
 
-	// Custom path
-	var customPath string
+	// Custom command; quote paths containing spaces
+	var customCmd string
 	err = huh.NewInput().
-		Title("Enter wrangler binary path:").
-		Value(&customPath).
+		Title("Enter wrangler command:").
+		Description("e.g. /opt/tools/wrangler or '/Users/me/My Tools/wrangler'").
+		Value(&customCmd).
+		Validate(func(line string) error {
+			_, err := ValidateCmdLine(line)
+			return err
+		}).
 		Run()
 
 	if err != nil {
-		return "", err
+		return Cmd{}, err
+	}
+
+	line, err := ValidateCmdLine(customCmd)
+	if err != nil {
+		return Cmd{}, err
+	}
+	cmd, err := ParseCmd(line)
+	if err != nil {
+		return Cmd{}, err
 	}
 
 	// Verify it works
-	if !tryWranglerCmd(customPath) {
-		return "", fmt.Errorf("could not run wrangler at: %s", customPath)
+	if _, err := checkWranglerCmd(cmd); err != nil {
+		return Cmd{}, fmt.Errorf("could not run wrangler with: %s", cmd)
 	}
 
-	return customPath, nil
+	return cmd, nil
 }
 
-// EnsureWranglerCmd makes sure we have a working wrangler command configured
-func EnsureWranglerCmd(db *store.AccountsDB) (string, error) {
+// cacheValid reports whether the cached check still vouches for cmd:
+// same command, same binary path and mtime, and checked within the TTL
+func cacheValid(state store.WranglerState, cmd Cmd) bool {
+	if state.Cmd != cmd.key() || time.Since(state.CheckedAt) > validationTTL {
+		return false
+	}
+	path, modTime, err := resolveBinary(cmd)
+	if err != nil {
+		return false
+	}
+	return path == state.Path && modTime.Equal(state.ModTime)
+}
+
+// recordCheck caches a successful check of cmd
+func recordCheck(db *store.AccountsDB, cmd Cmd, version string) {
+	path, modTime, err := resolveBinary(cmd)
+	if err != nil {
+		db.Wrangler = store.WranglerState{}
+		return
+	}
+	db.Wrangler = store.WranglerState{
+		Cmd:       cmd.key(),
+		Path:      path,
+		ModTime:   modTime,
+		Version:   version,
+		CheckedAt: time.Now(),
+	}
+}
+
+// Version returns the version of the configured wrangler command, checking
+// it again only if the cached check is stale. It returns "" if the command
+// isn't configured or doesn't run.
+func Version(db *store.AccountsDB) string {
+	cmd, err := ConfiguredCmd(db.Settings)
+	if err != nil || cmd.IsZero() {
+		return ""
+	}
+	if cacheValid(db.Wrangler, cmd) {
+		return db.Wrangler.Version
+	}
+
+	version, err := checkWranglerCmd(cmd)
+	if err != nil {
+		return ""
+	}
+	recordCheck(db, cmd, version)
+	store.SaveDB(db)
+	return version
+}
+
+// EnsureWranglerCmd returns the wrangler to run: the project's own wrangler
+// inside a project, otherwise the configured command, which it makes sure works.
+// A successful check is cached and only repeated when the resolved binary
+// changes or the cache is older than validationTTL.
+func EnsureWranglerCmd(db *store.AccountsDB) (Cmd, error) {
+	if cmd, ok := currentProjectCmd(db); ok {
+		return cmd, nil
+	}
+
 	// If already configured and works, use it
-	if db.Settings.WranglerCmd != "" {
-		if tryWranglerCmd(db.Settings.WranglerCmd) {
-			return db.Settings.WranglerCmd, nil
+	cmd, err := ConfiguredCmd(db.Settings)
+	if err != nil {
+		fmt.Printf("%v. Re-detecting...\n", err)
+	} else if !cmd.IsZero() {
+		if cacheValid(db.Wrangler, cmd) {
+			return cmd, nil
+		}
+		if version, err := checkWranglerCmd(cmd); err == nil {
+			recordCheck(db, cmd, version)
+			if err := store.SaveDB(db); err != nil {
+				return Cmd{}, fmt.Errorf("failed to save wrangler check: %w", err)
+			}
+			return cmd, nil
 		}
 		// Configured but doesn't work anymore, re-detect
-		fmt.Printf("Configured wrangler command '%s' no longer works. Re-detecting...\n", db.Settings.WranglerCmd)
+		fmt.Printf("Configured wrangler command '%s' no longer works. Re-detecting...\n", cmd)
 	}
 
 	// Detect or prompt
-	cmd, err := DetectWrangler()
+	cmd, version, err := detectWrangler()
 	if err != nil {
-		return "", err
+		return Cmd{}, err
 	}
 
-	// Save to settings
-	db.Settings.WranglerCmd = cmd
+	// Save to settings, keeping the extra env
+	cmd.Env = envList(db.Settings.WranglerEnv)
+	db.Settings.WranglerCmd = cmd.String()
+	db.Settings.WranglerArgv = nil
+	if version != "" {
+		recordCheck(db, cmd, version)
+	} else {
+		db.Wrangler = store.WranglerState{}
+	}
 	if err := store.SaveDB(db); err != nil {
-		return "", fmt.Errorf("failed to save wrangler command: %w", err)
+		return Cmd{}, fmt.Errorf("failed to save wrangler command: %w", err)
 	}
 
 	return cmd, nil
 }
 
+// EnsureProfileCmd returns the wrangler to run for a profile: the project's
+// own wrangler inside a project, then the profile's pinned command, then the
+// configured one. acc may be nil.
+func EnsureProfileCmd(db *store.AccountsDB, acc *store.Account) (Cmd, error) {
+	if cmd, ok := currentProjectCmd(db); ok {
+		return cmd, nil
+	}
+
+	if acc != nil && acc.WranglerCmd != "" {
+		cmd, err := ParseCmd(acc.WranglerCmd)
+		if err != nil {
+			return Cmd{}, fmt.Errorf("%s: %w", acc.Name, err)
+		}
+		cmd.Env = envList(db.Settings.WranglerEnv)
+		return cmd, nil
+	}
+
+	return EnsureWranglerCmd(db)
+}
+
+// currentProjectCmd returns the wrangler of the project around the working directory
+func currentProjectCmd(db *store.AccountsDB) (Cmd, bool) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return Cmd{}, false
+	}
+	cmd, ok := ProjectCmd(cwd)
+	if ok {
+		cmd.Env = envList(db.Settings.WranglerEnv)
+	}
+	return cmd, ok
+}
+
+// PinnedCmdLine turns a version like "3" or "3.78.2" into an npx command
+// for that wrangler version; anything else must be a valid command line
+func PinnedCmdLine(value string) (string, error) {
+	value = strings.TrimSpace(value)
+	if pinnedVersionRegex.MatchString(value) {
+		return "npx wrangler@" + value, nil
+	}
+	return ValidateCmdLine(value)
+}
+
+var pinnedVersionRegex = regexp.MustCompile(`^(latest|\d+(\.\d+){0,2}([-+][0-9A-Za-z.-]+)?)$`)
+
+// Exec runs wrangler with args attached to the terminal
+func Exec(ctx context.Context, cmd Cmd, args ...string) error {
+	c := cmd.command(args...)
+	c.Interactive = true
+	_, err := DefaultRunner.Run(ctx, c)
+	return err
+}
+
 // Whoami runs wrangler whoami and parses the output
-func Whoami(wranglerCmd string) (*WhoamiInfo, error) {
-	parts := strings.Fields(wranglerCmd)
-	args := append(parts[1:], "whoami")
+func Whoami(ctx context.Context, cmd Cmd) (*WhoamiInfo, error) {
+	ctx, cancel := context.WithTimeout(ctx, commandTimeout)
+	defer cancel()
 
-	cmd := exec.Command(parts[0], args...)
-	output, err := cmd.CombinedOutput()
+	output, err := DefaultRunner.Run(ctx, cmd.command("whoami"))
 	if err != nil {
-		return nil, fmt.Errorf("failed to run %s whoami: %w\nOutput: %s", wranglerCmd, err, string(output))
+		return nil, fmt.Errorf("failed to run %s whoami: %w\nOutput: %s", cmd, err, string(output))
 	}
 
 	return parseWhoamiOutput(string(output))
@@ -148,11 +360,13 @@ func parseWhoamiOutput(output string) (*WhoamiInfo, error) {
 			continue
 		}
 
-		// Found data row
+		// Found data row; the first one is the account we save
 		if name != "" && id != "" {
-			info.AccountName = name
-			info.AccountID = id
-			break
+			if info.AccountID == "" {
+				info.AccountName = name
+				info.AccountID = id
+			}
+			info.AccountIDs = append(info.AccountIDs, id)
 		}
 	}
 
@@ -163,28 +377,94 @@ func parseWhoamiOutput(output string) (*WhoamiInfo, error) {
 	return info, nil
 }
 
-// Login runs wrangler login interactively
-func Login(wranglerCmd string) error {
-	parts := strings.Fields(wranglerCmd)
-	args := append(parts[1:], "login")
+// Login runs wrangler login interactively. It isn't time-limited since it
+// waits for the user to finish in the browser.
+func Login(ctx context.Context, cmd Cmd) error {
+	c := cmd.command("login")
+	c.Interactive = true
+	_, err := DefaultRunner.Run(ctx, c)
+	return err
+}
+
+// Logout runs wrangler logout
+func Logout(ctx context.Context, cmd Cmd) error {
+	ctx, cancel := context.WithTimeout(ctx, commandTimeout)
+	defer cancel()
+
+	c := cmd.command("logout")
+	c.Interactive = true
+	_, err := DefaultRunner.Run(ctx, c)
+	return err
+}
+
+// RevokeToken revokes an OAuth refresh token the same way wrangler logout does,
+// without needing the token to be in wrangler's active config
+func RevokeToken(refreshToken string) error {
+	client := &http.Client{Timeout: 10 * time.Second}
 
-	cmd := exec.Command(parts[0], args...)
-	cmd.Stdin = os.Stdin
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
+	form := url.Values{}
+	form.Set("client_id", oauthClientID)
+	form.Set("token_type_hint", "refresh_token")
+	form.Set("token", refreshToken)
 
-	return cmd.Run()
+	resp, err := client.PostForm(revokeURL, form)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("token revocation failed: status %d", resp.StatusCode)
+	}
+	return nil
 }
 
-// Logout runs wrangler logout
-func Logout(wranglerCmd string) error {
-	parts := strings.Fields(wranglerCmd)
-	args := append(parts[1:], "logout")
+// OAuthToken is a refreshed OAuth token
+type OAuthToken struct {
+	AccessToken  string
+	RefreshToken string
+	ExpiresAt    time.Time
+}
 
-	cmd := exec.Command(parts[0], args...)
-	cmd.Stdin = os.Stdin
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
+// RefreshOAuthToken exchanges a refresh token for a new access token the
+// same way wrangler does when its token has expired
+func RefreshOAuthToken(refreshToken string) (*OAuthToken, error) {
+	client := &http.Client{Timeout: 10 * time.Second}
 
-	return cmd.Run()
+	form := url.Values{}
+	form.Set("grant_type", "refresh_token")
+	form.Set("client_id", oauthClientID)
+	form.Set("refresh_token", refreshToken)
+
+	resp, err := client.PostForm(tokenURL, form)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("token refresh failed: status %d", resp.StatusCode)
+	}
+
+	var body struct {
+		AccessToken  string `json:"access_token"`
+		RefreshToken string `json:"refresh_token"`
+		ExpiresIn    int    `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("invalid token response: %w", err)
+	}
+	if body.AccessToken == "" {
+		return nil, fmt.Errorf("token response has no access token")
+	}
+
+	token := &OAuthToken{
+		AccessToken:  body.AccessToken,
+		RefreshToken: body.RefreshToken,
+		ExpiresAt:    time.Now().Add(time.Duration(body.ExpiresIn) * time.Second),
+	}
+	if token.RefreshToken == "" {
+		token.RefreshToken = refreshToken
+	}
+	return token, nil
 }
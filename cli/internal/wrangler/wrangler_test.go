@@ -0,0 +1,179 @@
+package wrangler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+)
+
+const whoamiOutput = `
+ ⛅️ wrangler 3.78.2
+-------------------
+
+Getting User settings...
+👋 You are logged in with an OAuth Token, associated with the email dev@example.com.
+┌──────────────────┬──────────────────────────────────┐
+│ Account Name     │ Account ID                       │
+├──────────────────┼──────────────────────────────────┤
+│ Acme Dev         │ 0123456789abcdef0123456789abcdef │
+├──────────────────┼──────────────────────────────────┤
+│ Acme Prod        │ fedcba9876543210fedcba9876543210 │
+└──────────────────┴──────────────────────────────────┘
+`
+
+func TestParseWhoamiOutput(t *testing.T) {
+	info, err := parseWhoamiOutput(whoamiOutput)
+	if err != nil {
+		t.Fatalf("parseWhoamiOutput: %v", err)
+	}
+	if info.Email != "dev@example.com" {
+		t.Errorf("Email = %q, want dev@example.com", info.Email)
+	}
+	if info.AccountName != "Acme Dev" || info.AccountID != "0123456789abcdef0123456789abcdef" {
+		t.Errorf("account = %q %q, want the first row", info.AccountName, info.AccountID)
+	}
+	want := []string{"0123456789abcdef0123456789abcdef", "fedcba9876543210fedcba9876543210"}
+	if !slices.Equal(info.AccountIDs, want) {
+		t.Errorf("AccountIDs = %v, want %v", info.AccountIDs, want)
+	}
+}
+
+func TestParseWhoamiOutputNotLoggedIn(t *testing.T) {
+	_, err := parseWhoamiOutput("You are not authenticated. Please run `wrangler login`.\n")
+	if err == nil {
+		t.Fatal("expected an error without an account table")
+	}
+}
+
+func TestWhoami(t *testing.T) {
+	fake := useFakeRunner(t, map[string]fakeResponse{
+		"npx wrangler whoami": {Output: whoamiOutput},
+	})
+
+	cmd := Cmd{Argv: []string{"npx", "wrangler"}, Env: []string{"WRANGLER_LOG=debug"}}
+	info, err := Whoami(context.Background(), cmd)
+	if err != nil {
+		t.Fatalf("Whoami: %v", err)
+	}
+	if info.AccountID != "0123456789abcdef0123456789abcdef" {
+		t.Errorf("AccountID = %q", info.AccountID)
+	}
+
+	if len(fake.Calls) != 1 {
+		t.Fatalf("got %d calls, want 1", len(fake.Calls))
+	}
+	call := fake.Calls[0]
+	if call.Interactive {
+		t.Error("whoami should capture output, not attach the terminal")
+	}
+	if !slices.Equal(call.Env, cmd.Env) {
+		t.Errorf("Env = %v, want %v", call.Env, cmd.Env)
+	}
+}
+
+// TestHelperProcess stands in for wrangler when a test runs this test binary
+// through ExecRunner
+func TestHelperProcess(t *testing.T) {
+	if os.Getenv("CL_TEST_HELPER") != "1" {
+		return
+	}
+	fmt.Println("Authorization: Bearer abc.def-123")
+	fmt.Println("oauth_token = \"secret-token\"")
+	os.Exit(1)
+}
+
+// helperCmd runs TestHelperProcess as wrangler
+func helperCmd() Cmd {
+	return Cmd{
+		Argv: []string{os.Args[0], "-test.run=^TestHelperProcess$", "--"},
+		Env:  []string{"CL_TEST_HELPER=1"},
+	}
+}
+
+func TestWhoamiFailureRedactsOutput(t *testing.T) {
+	previous := DefaultRunner
+	DefaultRunner = ExecRunner{}
+	t.Cleanup(func() { DefaultRunner = previous })
+
+	_, err := Whoami(context.Background(), helperCmd())
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	for _, secret := range []string{"abc.def-123", "secret-token"} {
+		if strings.Contains(err.Error(), secret) {
+			t.Errorf("error leaks %q: %v", secret, err)
+		}
+	}
+	if !strings.Contains(err.Error(), "[REDACTED]") {
+		t.Errorf("error should keep the redacted output: %v", err)
+	}
+}
+
+func TestWhoamiCancelled(t *testing.T) {
+	useFakeRunner(t, map[string]fakeResponse{
+		"wrangler whoami": {Output: whoamiOutput},
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := Whoami(ctx, Cmd{Argv: []string{"wrangler"}}); !errors.Is(err, context.Canceled) {
+		t.Errorf("err = %v, want context.Canceled", err)
+	}
+}
+
+func TestLoginAndLogout(t *testing.T) {
+	for _, tt := range []struct {
+		command string
+		run     func(context.Context, Cmd) error
+	}{
+		{"login", Login},
+		{"logout", Logout},
+	} {
+		t.Run(tt.command, func(t *testing.T) {
+			fake := useFakeRunner(t, map[string]fakeResponse{
+				"wrangler " + tt.command: {},
+			})
+
+			if err := tt.run(context.Background(), Cmd{Argv: []string{"wrangler"}}); err != nil {
+				t.Fatalf("%s: %v", tt.command, err)
+			}
+			if len(fake.Calls) != 1 {
+				t.Fatalf("got %d calls, want 1", len(fake.Calls))
+			}
+			if call := fake.Calls[0]; !call.Interactive || !slices.Equal(call.Args, []string{tt.command}) {
+				t.Errorf("call = %+v, want interactive 'wrangler %s'", call, tt.command)
+			}
+		})
+	}
+}
+
+func TestLoginError(t *testing.T) {
+	useFakeRunner(t, map[string]fakeResponse{
+		"wrangler login": {Err: errors.New("exit status 1")},
+	})
+
+	if err := Login(context.Background(), Cmd{Argv: []string{"wrangler"}}); err == nil {
+		t.Fatal("expected the login error")
+	}
+}
+
+func TestRedact(t *testing.T) {
+	for _, tt := range []struct {
+		in, want string
+	}{
+		{`oauth_token = "abc123"`, `oauth_token = "[REDACTED]"`},
+		{`refresh_token: xyz`, `refresh_token: [REDACTED]`},
+		{`CLOUDFLARE_API_TOKEN=secret rest`, `CLOUDFLARE_API_TOKEN=[REDACTED] rest`},
+		{`cf_api_key=secret`, `cf_api_key=[REDACTED]`},
+		{`Authorization: Bearer abc.def-123`, `Authorization: Bearer [REDACTED]`},
+		{`nothing secret here`, `nothing secret here`},
+	} {
+		if got := string(Redact([]byte(tt.in))); got != tt.want {
+			t.Errorf("Redact(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}